@@ -0,0 +1,89 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"crushingviz.info/api/types/acled"
+)
+
+// codebookJSON is the event type taxonomy from ACLED's published codebook
+//
+//go:embed codebook.json
+var codebookJSON []byte
+
+// codebookEntry is an event type and its sub-event types as listed in the codebook
+type codebookEntry struct {
+	EventType     string   `json:"event_type"`
+	SubEventTypes []string `json:"sub_event_types"`
+}
+
+// codebook-check compares the acled package's event and sub-event type constants with the
+// embedded copy of ACLED's codebook and exits nonzero on any discrepancy
+func main() {
+	var codebook []codebookEntry
+	if err := json.Unmarshal(codebookJSON, &codebook); err != nil {
+		log.Fatalf("Failed to parse embedded codebook: %v", err)
+	}
+
+	discrepancies := check(codebook)
+	for _, discrepancy := range discrepancies {
+		fmt.Println(discrepancy)
+	}
+	if len(discrepancies) > 0 {
+		fmt.Fprintf(os.Stderr, "%d discrepancies between the codebook and the acled package\n", len(discrepancies))
+		os.Exit(1)
+	}
+	fmt.Println("acled package matches the codebook")
+}
+
+// check reports codebook entries missing from the code and code constants missing from the codebook
+func check(codebook []codebookEntry) []string {
+	discrepancies := make([]string, 0)
+
+	codebookSubEventTypes := make(map[string]map[string]bool, len(codebook))
+	for _, entry := range codebook {
+		subEventTypes := make(map[string]bool, len(entry.SubEventTypes))
+		for _, subEventType := range entry.SubEventTypes {
+			subEventTypes[subEventType] = true
+		}
+		codebookSubEventTypes[entry.EventType] = subEventTypes
+	}
+
+	codeEventTypes := make(map[string]bool)
+	for _, eventType := range acled.GetEventTypes() {
+		codeEventTypes[string(eventType)] = true
+		subEventTypes, ok := codebookSubEventTypes[string(eventType)]
+		if !ok {
+			discrepancies = append(discrepancies, fmt.Sprintf("event type %q is not in the codebook", eventType))
+			continue
+		}
+		for _, subEventType := range acled.SubEventTypesFor(eventType) {
+			if !subEventTypes[string(subEventType)] {
+				discrepancies = append(discrepancies,
+					fmt.Sprintf("sub-event type %q of %q is not in the codebook", subEventType, eventType))
+			}
+		}
+	}
+
+	for _, entry := range codebook {
+		if !codeEventTypes[entry.EventType] {
+			discrepancies = append(discrepancies, fmt.Sprintf("codebook event type %q is missing from the code", entry.EventType))
+			continue
+		}
+		codeSubEventTypes := make(map[string]bool)
+		for _, subEventType := range acled.SubEventTypesFor(acled.EventType(entry.EventType)) {
+			codeSubEventTypes[string(subEventType)] = true
+		}
+		for _, subEventType := range entry.SubEventTypes {
+			if !codeSubEventTypes[subEventType] {
+				discrepancies = append(discrepancies,
+					fmt.Sprintf("codebook sub-event type %q of %q is missing from the code", subEventType, entry.EventType))
+			}
+		}
+	}
+	return discrepancies
+}
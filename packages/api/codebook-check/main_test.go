@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// loadCodebook parses the embedded codebook
+func loadCodebook(t *testing.T) []codebookEntry {
+	t.Helper()
+	var codebook []codebookEntry
+	if err := json.Unmarshal(codebookJSON, &codebook); err != nil {
+		t.Fatal(err)
+	}
+	return codebook
+}
+
+func TestEmbeddedCodebookMatches(t *testing.T) {
+	if discrepancies := check(loadCodebook(t)); len(discrepancies) > 0 {
+		t.Errorf("acled package disagrees with the codebook:\n%s", strings.Join(discrepancies, "\n"))
+	}
+}
+
+func TestCheckReportsDiscrepancies(t *testing.T) {
+	codebook := loadCodebook(t)
+	// Drop one sub-event type, rename another event type's sub-event type and add an unknown event type
+	codebook[0].SubEventTypes = codebook[0].SubEventTypes[1:]
+	codebook[1].SubEventTypes = append([]string{"Renamed sub-event"}, codebook[1].SubEventTypes[1:]...)
+	codebook = append(codebook, codebookEntry{EventType: "Cyber attacks"})
+
+	got := strings.Join(check(codebook), "\n")
+	for _, want := range []string{
+		"is not in the codebook",
+		`codebook sub-event type "Renamed sub-event"`,
+		`codebook event type "Cyber attacks" is missing from the code`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("discrepancies %q don't mention %q", got, want)
+		}
+	}
+	if n := len(check(codebook)); n != 4 {
+		t.Errorf("got %d discrepancies, want 4:\n%s", n, got)
+	}
+}
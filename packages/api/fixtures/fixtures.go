@@ -0,0 +1,105 @@
+package fixtures
+
+import (
+	"math/rand"
+	"time"
+
+	"crushingviz.info/api/types/acled"
+)
+
+// FirstWeek is the week generated data starts from. It is fixed so output doesn't depend on the clock.
+var FirstWeek = time.Date(2024, time.January, 6, 0, 0, 0, 0, time.UTC)
+
+// maxSubEventsPerWeek bounds how many distinct sub-event types an area reports in one week
+const maxSubEventsPerWeek = 3
+
+// Generate produces plausible pseudo-random weekly aggregates for the given areas, starting at
+// FirstWeek and covering the given number of weeks. The same seed always yields the same rows.
+// Rows pair sub-events with their event and disorder types, and place centroids inside the area's
+// bounding box when it has geometry. Areas are linked to their country and region through ParentID
+// when those parents are included in areas.
+func Generate(seed int64, weeks int, areas []acled.GeographicArea) []acled.ACLEDWeeklyAggregate {
+	rng := rand.New(rand.NewSource(seed))
+
+	subEventTypes := acled.MatchSubEventTypes("")
+	areasByID := make(map[int]acled.GeographicArea, len(areas))
+	for _, area := range areas {
+		areasByID[area.ID] = area
+	}
+
+	rows := make([]acled.ACLEDWeeklyAggregate, 0)
+	for w := 0; w < weeks; w++ {
+		week := FirstWeek.AddDate(0, 0, 7*w)
+		for _, area := range areas {
+			regionID, countryID, admin1ID := hierarchy(area, areasByID)
+
+			count := rng.Intn(maxSubEventsPerWeek + 1)
+			for _, i := range rng.Perm(len(subEventTypes))[:count] {
+				subEventType := subEventTypes[i]
+				eventType, _ := acled.EventTypeForSubEventType(subEventType)
+				disorderType, _ := acled.DisorderTypeForSubEventType(subEventType)
+
+				eventCount := uint64(1 + rng.Intn(20))
+				var fatalities uint64
+				if disorderType == acled.DisorderTypePoliticalViolence {
+					fatalities = uint64(rng.Intn(int(eventCount)*3 + 1))
+				}
+				lon, lat := centroid(rng, area)
+
+				rows = append(rows, acled.ACLEDWeeklyAggregate{
+					Week:               week,
+					RegionID:           regionID,
+					CountryID:          countryID,
+					Admin1ID:           admin1ID,
+					DisorderType:       disorderType,
+					EventType:          eventType,
+					SubEventType:       subEventType,
+					EventCount:         eventCount,
+					Fatalities:         fatalities,
+					PopulationExposure: uint64(rng.Intn(500000)),
+					CentroidLongitude:  lon,
+					CentroidLatitude:   lat,
+				})
+			}
+		}
+	}
+	return rows
+}
+
+// hierarchy resolves the region, country and admin1 IDs for an area by walking its parents
+func hierarchy(area acled.GeographicArea, areasByID map[int]acled.GeographicArea) (int, *int, *int) {
+	var regionID int
+	var countryID, admin1ID *int
+
+	current, seen := area, make(map[int]bool)
+	for !seen[current.ID] {
+		seen[current.ID] = true
+		id := current.ID
+		switch current.Type {
+		case acled.GeographicAreaTypeAdmin1:
+			admin1ID = &id
+		case acled.GeographicAreaTypeCountry:
+			countryID = &id
+		}
+		regionID = id
+
+		if current.ParentID == nil {
+			break
+		}
+		parent, ok := areasByID[*current.ParentID]
+		if !ok {
+			break
+		}
+		current = parent
+	}
+	return regionID, countryID, admin1ID
+}
+
+// centroid picks a point inside the area's bounding box, or anywhere when it has no geometry
+func centroid(rng *rand.Rand, area acled.GeographicArea) (float64, float64) {
+	minLon, minLat, maxLon, maxLat, ok := area.BoundingBox()
+	if !ok {
+		minLon, minLat, maxLon, maxLat = -180, -90, 180, 90
+	}
+	return minLon + rng.Float64()*(maxLon-minLon), minLat + rng.Float64()*(maxLat-minLat)
+}
@@ -0,0 +1,58 @@
+package fixtures
+
+import (
+	"reflect"
+	"testing"
+
+	"crushingviz.info/api/types/acled"
+)
+
+// testAreas returns a region, a country in it and an admin1 area with geometry in the country
+func testAreas() []acled.GeographicArea {
+	region, country := 1, 2
+	return []acled.GeographicArea{
+		{ID: 1, Name: "Eastern Africa", Type: acled.GeographicAreaTypeRegion},
+		{ID: 2, Name: "Kenya", Type: acled.GeographicAreaTypeCountry, ParentID: &region},
+		{ID: 3, Name: "Nairobi", Type: acled.GeographicAreaTypeAdmin1, ParentID: &country,
+			GeoJSON: `{"type":"Polygon","coordinates":[[[36.6,-1.45],[37.1,-1.45],[37.1,-1.15],[36.6,-1.15],[36.6,-1.45]]]}`},
+	}
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	first := Generate(42, 12, testAreas())
+	if len(first) == 0 {
+		t.Fatal("Generate produced no rows")
+	}
+	if second := Generate(42, 12, testAreas()); !reflect.DeepEqual(first, second) {
+		t.Error("the same seed produced different rows")
+	}
+	if other := Generate(43, 12, testAreas()); reflect.DeepEqual(first, other) {
+		t.Error("different seeds produced the same rows")
+	}
+}
+
+func TestGenerateProducesValidRows(t *testing.T) {
+	areas := testAreas()
+	minLon, minLat, maxLon, maxLat, _ := areas[2].BoundingBox()
+
+	for i, row := range Generate(7, 20, areas) {
+		if err := row.Validate(); err != nil {
+			t.Errorf("row %d is invalid: %v", i, err)
+		}
+		if row.RegionID != 1 {
+			t.Errorf("row %d region = %d, want 1", i, row.RegionID)
+		}
+		if row.Admin1ID != nil && *row.Admin1ID == 3 {
+			if *row.CountryID != 2 {
+				t.Errorf("row %d country = %d, want 2", i, *row.CountryID)
+			}
+			if row.CentroidLongitude < minLon || row.CentroidLongitude > maxLon ||
+				row.CentroidLatitude < minLat || row.CentroidLatitude > maxLat {
+				t.Errorf("row %d centroid (%v, %v) is outside Nairobi", i, row.CentroidLongitude, row.CentroidLatitude)
+			}
+		}
+		if row.EventCount == 0 {
+			t.Errorf("row %d has no events", i)
+		}
+	}
+}
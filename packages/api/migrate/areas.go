@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"crushingviz.info/api/types/acled"
+)
+
+// areaProperties are the geographic_area columns carried by an exported feature. The parent is
+// referenced by type and name rather than ID so the file can be imported into another database.
+type areaProperties struct {
+	ACLEDCode  *int                     `json:"acled_code,omitempty"`
+	Name       string                   `json:"name"`
+	Type       acled.GeographicAreaType `json:"type"`
+	ISO        *string                  `json:"iso,omitempty"`
+	ParentType acled.GeographicAreaType `json:"parent_type,omitempty"`
+	ParentName string                   `json:"parent_name,omitempty"`
+}
+
+// areaFeature is one geographic_area row as a GeoJSON Feature
+type areaFeature struct {
+	Type       string          `json:"type"`
+	Properties areaProperties  `json:"properties"`
+	Geometry   json.RawMessage `json:"geometry"`
+}
+
+// areaFeatureCollection is the file format of ExportAreas and ImportAreas
+type areaFeatureCollection struct {
+	Type     string        `json:"type"`
+	Features []areaFeature `json:"features"`
+}
+
+// areaTypeRank orders area types so parents are inserted before their children
+var areaTypeRank = map[acled.GeographicAreaType]int{
+	acled.GeographicAreaTypeRegion:  0,
+	acled.GeographicAreaTypeCountry: 1,
+	acled.GeographicAreaTypeAdmin1:  2,
+}
+
+// areaKey identifies an area by type and name, matching the unique index on geographic_area
+func areaKey(areaType acled.GeographicAreaType, name string) string {
+	return string(areaType) + "\x00" + name
+}
+
+// ExportAreas writes every geographic_area row to w as a GeoJSON FeatureCollection, ordered so
+// parents come before their children
+func (m *Migrator) ExportAreas(w io.Writer) error {
+	rows, err := m.db.QueryContext(context.Background(), `
+        SELECT a.acled_code, a.name, a.type, a.iso, COALESCE(p.type::text, ''), COALESCE(p.name, ''), a.geojson
+        FROM geographic_area a
+        LEFT JOIN geographic_area p ON p.id = a.parent_id
+        ORDER BY CASE a.type WHEN 'region' THEN 0 WHEN 'country' THEN 1 ELSE 2 END, a.name
+    `)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	collection := areaFeatureCollection{Type: "FeatureCollection", Features: make([]areaFeature, 0)}
+	for rows.Next() {
+		var properties areaProperties
+		var acledCode sql.NullInt64
+		var iso sql.NullString
+		var geoJSON []byte
+		if err := rows.Scan(&acledCode, &properties.Name, &properties.Type, &iso,
+			&properties.ParentType, &properties.ParentName, &geoJSON); err != nil {
+			return err
+		}
+		if acledCode.Valid {
+			code := int(acledCode.Int64)
+			properties.ACLEDCode = &code
+		}
+		if iso.Valid {
+			properties.ISO = &iso.String
+		}
+
+		geometry, err := featureGeometry(geoJSON)
+		if err != nil {
+			return fmt.Errorf("area %q: %w", properties.Name, err)
+		}
+		collection.Features = append(collection.Features, areaFeature{
+			Type:       "Feature",
+			Properties: properties,
+			Geometry:   geometry,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(collection)
+}
+
+// featureGeometry returns the geometry of stored GeoJSON, unwrapping a Feature, or null when empty
+func featureGeometry(geoJSON []byte) (json.RawMessage, error) {
+	if len(geoJSON) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	var object struct {
+		Type     string          `json:"type"`
+		Geometry json.RawMessage `json:"geometry"`
+	}
+	if err := json.Unmarshal(geoJSON, &object); err != nil {
+		return nil, err
+	}
+	if object.Type == "Feature" {
+		if len(object.Geometry) == 0 {
+			return json.RawMessage("null"), nil
+		}
+		return object.Geometry, nil
+	}
+	return json.RawMessage(geoJSON), nil
+}
+
+// ImportAreas inserts the areas of a FeatureCollection written by ExportAreas in one transaction.
+// Regions are inserted before countries and countries before admin1s, and each parent is resolved
+// by type and name among the imported areas and those already in the table. Geometries are
+// validated before anything is written. It returns the number of areas inserted.
+func (m *Migrator) ImportAreas(r io.Reader) (int, error) {
+	ctx := context.Background()
+
+	var collection areaFeatureCollection
+	if err := json.NewDecoder(r).Decode(&collection); err != nil {
+		return 0, fmt.Errorf("failed to decode feature collection: %w", err)
+	}
+
+	for _, feature := range collection.Features {
+		if _, ok := areaTypeRank[feature.Properties.Type]; !ok {
+			return 0, fmt.Errorf("area %q has unknown type %q", feature.Properties.Name, feature.Properties.Type)
+		}
+		area := acled.GeographicArea{Name: feature.Properties.Name}
+		if len(feature.Geometry) > 0 && string(feature.Geometry) != "null" {
+			area.GeoJSON = feature.Geometry
+		}
+		if err := area.ValidateGeometry(); err != nil {
+			return 0, err
+		}
+	}
+	sort.SliceStable(collection.Features, func(i, j int) bool {
+		return areaTypeRank[collection.Features[i].Properties.Type] < areaTypeRank[collection.Features[j].Properties.Type]
+	})
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	ids := make(map[string]int, len(collection.Features))
+	for _, feature := range collection.Features {
+		properties := feature.Properties
+
+		var parentID *int
+		if properties.ParentName != "" {
+			key := areaKey(properties.ParentType, properties.ParentName)
+			id, ok := ids[key]
+			if !ok {
+				err := tx.QueryRowContext(ctx, `SELECT id FROM geographic_area WHERE type = $1 AND name = $2`,
+					properties.ParentType, properties.ParentName).Scan(&id)
+				if err != nil {
+					return 0, fmt.Errorf("failed to resolve parent %s %q of %q: %w",
+						properties.ParentType, properties.ParentName, properties.Name, err)
+				}
+				ids[key] = id
+			}
+			parentID = &id
+		}
+
+		var geoJSON any
+		if len(feature.Geometry) > 0 && string(feature.Geometry) != "null" {
+			geoJSON = []byte(feature.Geometry)
+		}
+
+		var id int
+		err := tx.QueryRowContext(ctx, `
+            INSERT INTO geographic_area (acled_code, name, type, iso, parent_id, geojson)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            RETURNING id
+        `, properties.ACLEDCode, properties.Name, properties.Type, properties.ISO, parentID, geoJSON).Scan(&id)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert area %q: %w", properties.Name, err)
+		}
+		ids[areaKey(properties.Type, properties.Name)] = id
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(collection.Features), nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"crushingviz.info/api/types/acled"
+)
+
+// seedAreas adds a region, two countries under it and an admin1 under one of them
+func seedAreas(db *fakeDB) {
+	code, iso := 404, "KEN"
+	region := 1
+	kenya := 2
+	db.areas = []fakeArea{
+		{id: 1, name: "Eastern Africa", areaType: acled.GeographicAreaTypeRegion,
+			geoJSON: []byte(`{"type":"Polygon","coordinates":[[[20,-20],[60,-20],[60,20],[20,20],[20,-20]]]}`)},
+		{id: 2, acledCode: &code, name: "Kenya", areaType: acled.GeographicAreaTypeCountry, iso: &iso, parentID: &region,
+			geoJSON: []byte(`{"type":"Feature","geometry":{"type":"Polygon","coordinates":[[[34,-4],[40,-4],[40,2],[34,2],[34,-4]]]}}`)},
+		{id: 3, name: "Nairobi", areaType: acled.GeographicAreaTypeAdmin1, parentID: &kenya,
+			geoJSON: []byte(`{"type":"Polygon","coordinates":[[[36.5,-1.5],[37,-1.5],[37,-1],[36.5,-1],[36.5,-1.5]]]}`)},
+		{id: 4, name: "Somalia", areaType: acled.GeographicAreaTypeCountry, parentID: &region},
+	}
+}
+
+func TestAreasRoundTrip(t *testing.T) {
+	source, sourceDB := newTestMigrator(nil)
+	seedAreas(sourceDB)
+	var exported bytes.Buffer
+	if err := source.ExportAreas(&exported); err != nil {
+		t.Fatal(err)
+	}
+
+	// Import the features children first, so the import has to order them itself
+	var collection areaFeatureCollection
+	if err := json.Unmarshal(exported.Bytes(), &collection); err != nil {
+		t.Fatal(err)
+	}
+	for i, j := 0, len(collection.Features)-1; i < j; i, j = i+1, j-1 {
+		collection.Features[i], collection.Features[j] = collection.Features[j], collection.Features[i]
+	}
+	reversed, err := json.Marshal(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target, targetDB := newTestMigrator(nil)
+	imported, err := target.ImportAreas(bytes.NewReader(reversed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != 4 {
+		t.Errorf("imported %d areas, want 4", imported)
+	}
+
+	ids := make(map[string]int)
+	for i, area := range targetDB.areas {
+		ids[area.name] = area.id
+		if i > 0 && areaTypeRank[area.areaType] < areaTypeRank[targetDB.areas[i-1].areaType] {
+			t.Errorf("%s %q was inserted after %s %q", area.areaType, area.name,
+				targetDB.areas[i-1].areaType, targetDB.areas[i-1].name)
+		}
+	}
+	for child, parent := range map[string]string{"Kenya": "Eastern Africa", "Somalia": "Eastern Africa", "Nairobi": "Kenya"} {
+		area := targetDB.areas[ids[child]-1]
+		if area.parentID == nil || *area.parentID != ids[parent] {
+			t.Errorf("%s parent ID = %v, want %d (%s)", child, area.parentID, ids[parent], parent)
+		}
+	}
+	if !targetDB.executed("COMMIT") {
+		t.Error("import was not committed")
+	}
+
+	var reexported bytes.Buffer
+	if err := target.ExportAreas(&reexported); err != nil {
+		t.Fatal(err)
+	}
+	if reexported.String() != exported.String() {
+		t.Errorf("re-exported areas differ:\n%s\nwant\n%s", reexported.String(), exported.String())
+	}
+}
+
+func TestImportAreasRejectsInvalidInput(t *testing.T) {
+	for name, file := range map[string]string{
+		"unclosed ring": `{"type":"FeatureCollection","features":[{"type":"Feature","properties":{"name":"Bad","type":"region"},
+            "geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1]]]}}]}`,
+		"unknown type": `{"type":"FeatureCollection","features":[{"type":"Feature","properties":{"name":"Bad","type":"city"},"geometry":null}]}`,
+		"missing parent": `{"type":"FeatureCollection","features":[{"type":"Feature",
+            "properties":{"name":"Nairobi","type":"admin_1","parent_type":"country","parent_name":"Kenya"},"geometry":null}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			migrator, db := newTestMigrator(nil)
+			if _, err := migrator.ImportAreas(strings.NewReader(file)); err == nil {
+				t.Fatal("expected an error")
+			}
+			if len(db.areas) != 0 || db.executed("COMMIT") {
+				t.Errorf("failed import wrote areas: %q", db.statements)
+			}
+		})
+	}
+}
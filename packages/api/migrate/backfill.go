@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"crushingviz.info/api/types/acled"
+)
+
+// defaultBackfillBatchSize is how many rows BackfillDisorder corrects per transaction by default
+const defaultBackfillBatchSize = 1000
+
+// backfillDisorderSQL corrects up to $3 rows of one event type whose disorder_type isn't $1. Postgres
+// has no UPDATE ... LIMIT, so the batch is picked by ctid.
+const backfillDisorderSQL = `
+    UPDATE acled_weekly_agg SET disorder_type = $1::disorder_type
+    WHERE ctid IN (
+        SELECT ctid FROM acled_weekly_agg
+        WHERE event_type = $2::event_type AND disorder_type IS DISTINCT FROM $1::disorder_type
+        LIMIT $3
+    )`
+
+// BackfillDisorder recomputes disorder_type from event_type for acled_weekly_agg rows written by a
+// buggy import, using acled.DisorderTypeForEventType. Only rows whose stored disorder type differs
+// are updated, batchSize rows at a time, each batch in its own transaction so a long backfill
+// doesn't hold locks on the whole table. Riots are skipped because their disorder type depends on
+// the sub-event type. It returns the number of rows corrected, including those of batches that
+// committed before an error. disorder_type is part of the primary key, so a row whose corrected key
+// already exists fails its batch and has to be merged by hand.
+func (m *Migrator) BackfillDisorder(batchSize int) (int, error) {
+	if batchSize < 1 {
+		return 0, fmt.Errorf("batch size must be at least 1, got %d", batchSize)
+	}
+	ctx := context.Background()
+
+	corrected := 0
+	for _, eventType := range acled.GetEventTypes() {
+		disorderType, ok := acled.DisorderTypeForEventType(eventType)
+		if !ok {
+			continue
+		}
+		for {
+			tx, err := m.db.BeginTx(ctx, nil)
+			if err != nil {
+				return corrected, err
+			}
+			result, err := tx.ExecContext(ctx, backfillDisorderSQL, string(disorderType), string(eventType), batchSize)
+			if err != nil {
+				tx.Rollback()
+				return corrected, fmt.Errorf("failed to backfill disorder type of %s: %w", eventType, err)
+			}
+			rows, err := result.RowsAffected()
+			if err != nil {
+				tx.Rollback()
+				return corrected, err
+			}
+			if err := tx.Commit(); err != nil {
+				return corrected, err
+			}
+			corrected += int(rows)
+			if rows < int64(batchSize) {
+				break
+			}
+		}
+	}
+	return corrected, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"crushingviz.info/api/types/acled"
+)
+
+func TestBackfillDisorder(t *testing.T) {
+	row := func(disorderType acled.DisorderType, eventType acled.EventType, subEventType acled.SubEventType) acled.ACLEDWeeklyAggregate {
+		return acled.ACLEDWeeklyAggregate{RegionID: 1, DisorderType: disorderType, EventType: eventType, SubEventType: subEventType}
+	}
+	migrator, db := newTestMigrator(nil)
+	db.aggregates = []acled.ACLEDWeeklyAggregate{
+		// Wrong
+		row(acled.DisorderTypeDemonstrations, acled.EventTypeBattles, acled.SubEventTypeBattlesArmedClash),
+		row(acled.DisorderTypeStrategic, acled.EventTypeBattles, acled.SubEventTypeBattlesArmedClash),
+		row("", acled.EventTypeBattles, acled.SubEventTypeBattlesArmedClash),
+		row(acled.DisorderTypePoliticalViolence, acled.EventTypeProtests, acled.SubEventTypeProtestsPeacefulProtest),
+		// Right
+		row(acled.DisorderTypePoliticalViolence, acled.EventTypeBattles, acled.SubEventTypeBattlesArmedClash),
+		row(acled.DisorderTypeDemonstrations, acled.EventTypeProtests, acled.SubEventTypeProtestsPeacefulProtest),
+		// Riots are ambiguous and left alone, even when they look wrong
+		row(acled.DisorderTypeStrategic, acled.EventTypeRiots, acled.SubEventTypeRiotsMobViolence),
+	}
+
+	corrected, err := migrator.BackfillDisorder(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corrected != 4 {
+		t.Errorf("corrected %d rows, want 4", corrected)
+	}
+	for i, aggregate := range db.aggregates[:6] {
+		if want, _ := acled.DisorderTypeForEventType(aggregate.EventType); aggregate.DisorderType != want {
+			t.Errorf("row %d disorder type = %q, want %q", i, aggregate.DisorderType, want)
+		}
+	}
+	if riot := db.aggregates[6]; riot.DisorderType != acled.DisorderTypeStrategic {
+		t.Errorf("riot row disorder type = %q, want it untouched", riot.DisorderType)
+	}
+
+	// The three wrong Battles rows take two batches of 2, each in its own transaction
+	batches := 0
+	for i, statement := range db.statements {
+		if strings.Contains(statement, "UPDATE acled_weekly_agg") {
+			batches++
+			if db.statements[i-1] != "BEGIN" || db.statements[i+1] != "COMMIT" {
+				t.Errorf("batch %d wasn't run in its own transaction: %q", batches, db.statements)
+			}
+		}
+	}
+	// Battles twice, then once for each other unambiguous event type
+	if batches != 6 {
+		t.Errorf("ran %d batches, want 6", batches)
+	}
+
+	// A second run finds nothing to correct
+	if corrected, err := migrator.BackfillDisorder(2); err != nil || corrected != 0 {
+		t.Errorf("second run corrected %d rows, %v, want 0", corrected, err)
+	}
+	if _, err := migrator.BackfillDisorder(0); err == nil {
+		t.Error("expected an error for a batch size of 0")
+	}
+}
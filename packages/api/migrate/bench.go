@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// BenchRun is one timed application of a migration
+type BenchRun struct {
+	Duration time.Duration
+	// RowsAffected is reported by the driver for the migration's last statement, or -1 if unavailable
+	RowsAffected int64
+}
+
+// Bench times applying one migration against the connected database, which should be a scratch
+// copy of production data. Each run happens in its own transaction that is rolled back, so the
+// database is reset between runs. If the migration is already applied its down SQL is run first,
+// untimed, so the up SQL can be replayed.
+func (m *Migrator) Bench(version int, runs int) ([]BenchRun, error) {
+	ctx := context.Background()
+
+	var migration *Migration
+	for _, mg := range m.migrations {
+		if mg.Version == version {
+			migration = mg
+			break
+		}
+	}
+	if migration == nil {
+		return nil, fmt.Errorf("migration %d not found", version)
+	}
+	if runs < 1 {
+		return nil, fmt.Errorf("runs must be at least 1, got %d", runs)
+	}
+
+	if err := m.Initialize(); err != nil {
+		return nil, err
+	}
+	currentVersion, err := m.GetCurrentVersion()
+	if err != nil {
+		return nil, err
+	}
+	applied := version <= currentVersion
+	if applied && migration.DownSQL == "" {
+		return nil, fmt.Errorf("migration %d is applied and has no down SQL to reset it", version)
+	}
+
+	results := make([]BenchRun, 0, runs)
+	for i := 0; i < runs; i++ {
+		run, err := m.benchOnce(ctx, migration, applied)
+		if err != nil {
+			return results, fmt.Errorf("run %d: %w", i+1, err)
+		}
+		results = append(results, run)
+	}
+	return results, nil
+}
+
+// benchOnce applies the migration once inside a transaction that is always rolled back
+func (m *Migrator) benchOnce(ctx context.Context, migration *Migration, applied bool) (BenchRun, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return BenchRun{}, err
+	}
+	defer tx.Rollback()
+
+	if applied {
+		if _, err := tx.ExecContext(ctx, migration.DownSQL); err != nil {
+			return BenchRun{}, fmt.Errorf("failed to revert migration %d before benchmarking: %w", migration.Version, err)
+		}
+	}
+	if migration.LockTimeout > 0 {
+		if _, err := tx.ExecContext(ctx, lockTimeoutSQL(migration.LockTimeout)); err != nil {
+			return BenchRun{}, fmt.Errorf("failed to set lock timeout for migration %d: %w", migration.Version, err)
+		}
+	}
+
+	start := time.Now()
+	result, err := tx.ExecContext(ctx, migration.UpSQL)
+	if err != nil {
+		return BenchRun{}, fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Description, err)
+	}
+	run := BenchRun{Duration: time.Since(start), RowsAffected: -1}
+	if rowsAffected, err := result.RowsAffected(); err == nil {
+		run.RowsAffected = rowsAffected
+	}
+	return run, nil
+}
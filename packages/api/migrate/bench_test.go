@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBench(t *testing.T) {
+	migrator, db := newTestMigrator(testMigrations())
+	if err := migrator.UpToVersion(2); err != nil {
+		t.Fatal(err)
+	}
+	statements := len(db.statements)
+
+	runs, err := migrator.Bench(2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 3 {
+		t.Fatalf("Bench returned %d runs, want 3", len(runs))
+	}
+	for i, run := range runs {
+		if run.RowsAffected != 1 {
+			t.Errorf("run %d rows affected = %d, want the fake's 1", i, run.RowsAffected)
+		}
+	}
+
+	// Each run reverts the applied migration, replays it and rolls back
+	var rollbacks, downs int
+	for _, statement := range db.statements[statements:] {
+		switch {
+		case statement == "ROLLBACK":
+			rollbacks++
+		case strings.Contains(statement, "DROP TABLE beta"):
+			downs++
+		case statement == "COMMIT":
+			t.Error("Bench committed a run")
+		}
+	}
+	if rollbacks != 3 || downs != 3 {
+		t.Errorf("Bench ran %d downs and %d rollbacks, want 3 of each", downs, rollbacks)
+	}
+	if got := db.versions(); len(got) != 2 {
+		t.Errorf("Bench changed the recorded versions to %v", got)
+	}
+}
+
+func TestBenchRejectsInvalidInput(t *testing.T) {
+	migrator, _ := newTestMigrator(testMigrations())
+	if _, err := migrator.Bench(9, 1); err == nil {
+		t.Error("Bench accepted an unknown version")
+	}
+	if _, err := migrator.Bench(1, 0); err == nil {
+		t.Error("Bench accepted 0 runs")
+	}
+
+	if err := migrator.UpToVersion(1); err != nil {
+		t.Fatal(err)
+	}
+	migrator.migrations[0].DownSQL = ""
+	if _, err := migrator.Bench(1, 1); err == nil {
+		t.Error("Bench accepted an applied migration without down SQL")
+	}
+}
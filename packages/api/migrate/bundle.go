@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteUpBundle writes a single SQL script applying the loaded migrations above fromVersion up to
+// toVersion, for environments where only one file can be run. The script runs in one transaction,
+// creates schema_migrations if needed and records each migration right after its SQL, so the
+// tracking table is correct once it commits. Tables named by analyze directives are analyzed after
+// the commit. Migrations excluded by the tag filter are left out.
+// Unlike UpToVersion the Postgres version requirements of migrations aren't checked.
+func (m *Migrator) WriteUpBundle(w io.Writer, fromVersion int, toVersion int) error {
+	return m.writeUpBundle(w, fromVersion, toVersion, "")
+}
+
+// emptyDatabaseGuardSQL aborts a script's transaction when migrations have already been recorded
+const emptyDatabaseGuardSQL = `DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM schema_migrations) THEN
+        RAISE EXCEPTION 'schema_migrations is not empty, this script only initializes new databases';
+    END IF;
+END
+$$;`
+
+// WriteInitScript writes a single SQL script that brings an empty database to the latest version,
+// for onboarding. It is the up bundle of every loaded migration with a guard that aborts, before
+// anything is changed, when the database already has migrations recorded, so running it twice is
+// harmless.
+func (m *Migrator) WriteInitScript(w io.Writer) error {
+	latest := 0
+	for _, migration := range m.migrations {
+		latest = max(latest, migration.Version)
+	}
+	return m.writeUpBundle(w, 0, latest, emptyDatabaseGuardSQL)
+}
+
+// writeUpBundle writes an up bundle, running guard right after schema_migrations is initialized
+func (m *Migrator) writeUpBundle(w io.Writer, fromVersion int, toVersion int, guard string) error {
+	var b strings.Builder
+	analyzeTables := make([]string, 0)
+	b.WriteString("BEGIN;\n")
+	b.WriteString(strings.TrimSpace(initializeSQL) + "\n")
+	if guard != "" {
+		b.WriteString(guard + "\n")
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version <= fromVersion || migration.Version > toVersion || !m.matchesTags(migration) {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n-- Migration %d: %s\n", migration.Version, migration.Description)
+		if migration.LockTimeout > 0 {
+			b.WriteString(lockTimeoutSQL(migration.LockTimeout) + ";\n")
+		}
+		b.WriteString(terminatedSQL(migration.UpSQL) + "\n")
+		if migration.LockTimeout > 0 {
+			b.WriteString(resetLockTimeoutSQL + ";\n")
+		}
+
+		tags := make([]string, len(migration.Tags))
+		for i, tag := range migration.Tags {
+			tags[i] = quoteString(tag)
+		}
+		appliedBy := "CURRENT_USER"
+		if m.appliedBy != "" {
+			appliedBy = quoteString(m.appliedBy)
+		}
+		fmt.Fprintf(&b, "INSERT INTO schema_migrations (version, description, applied_at, tags, checksum, applied_by)\n")
+		fmt.Fprintf(&b, "VALUES (%d, %s, NOW(), ARRAY[%s]::TEXT[], %s, %s);\n", migration.Version,
+			quoteString(migration.Description), strings.Join(tags, ", "), quoteString(migration.Checksum()), appliedBy)
+		analyzeTables = appendUnique(analyzeTables, migration.AnalyzeTables...)
+	}
+
+	b.WriteString("\nCOMMIT;\n")
+	for _, table := range analyzeTables {
+		b.WriteString(analyzeSQL(table) + ";\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// terminatedSQL trims a migration's SQL and ends it with a semicolon, so a last statement without
+// one doesn't run into the INSERT or DELETE that follows it in a bundle
+func terminatedSQL(sql string) string {
+	sql = strings.TrimSpace(sql)
+	if !strings.HasSuffix(sql, ";") {
+		sql += ";"
+	}
+	return sql
+}
+
+// WriteDownBundle writes a single SQL script reverting the loaded migrations above toVersion up to
+// fromVersion, newest first, removing each from schema_migrations right after its down SQL. It
+// fails if any of them has no down SQL. The script is built from the files alone, so fromVersion
+// should be the database's current version and any holes left by skipped migrations must be
+// handled by hand.
+func (m *Migrator) WriteDownBundle(w io.Writer, fromVersion int, toVersion int) error {
+	var b strings.Builder
+	b.WriteString("BEGIN;\n")
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version > fromVersion || migration.Version <= toVersion {
+			continue
+		}
+		if strings.TrimSpace(migration.DownSQL) == "" {
+			return fmt.Errorf("down migration SQL is empty for version %d", migration.Version)
+		}
+
+		fmt.Fprintf(&b, "\n-- Revert migration %d: %s\n", migration.Version, migration.Description)
+		b.WriteString(terminatedSQL(migration.DownSQL) + "\n")
+		fmt.Fprintf(&b, "DELETE FROM schema_migrations WHERE version = %d;\n", migration.Version)
+	}
+
+	b.WriteString("\nCOMMIT;\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
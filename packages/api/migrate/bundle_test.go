@@ -0,0 +1,213 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scratchSchema connects to TEST_POSTGRES_CONNECTION_STRING with a new, empty schema first in
+// search_path, dropped at the end of the test. The test is skipped when the variable isn't set.
+func scratchSchema(t *testing.T) *sql.DB {
+	t.Helper()
+	connStr := os.Getenv("TEST_POSTGRES_CONNECTION_STRING")
+	if connStr == "" {
+		t.Skip("TEST_POSTGRES_CONNECTION_STRING is not set")
+	}
+	conn, err := sql.Open("postgres", connStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// search_path belongs to the session, so keep to one connection
+	conn.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("scratch_%d", time.Now().UnixNano())
+	if _, err := conn.Exec("CREATE SCHEMA " + schema + "; SET search_path TO " + schema); err != nil {
+		conn.Close()
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Exec("DROP SCHEMA " + schema + " CASCADE")
+		conn.Close()
+	})
+	return conn
+}
+
+// recordedVersions returns the versions in schema_migrations, in order
+func recordedVersions(t *testing.T, conn *sql.DB) []int {
+	t.Helper()
+	rows, err := conn.Query("SELECT version FROM schema_migrations ORDER BY version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	versions := make([]int, 0)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			t.Fatal(err)
+		}
+		versions = append(versions, version)
+	}
+	return versions
+}
+
+func TestWriteUpBundle(t *testing.T) {
+	migrations := testMigrations()
+	migrations[1].Tags = []string{"seed"}
+	migrator, db := newTestMigrator(migrations)
+	migrator.SetTagFilter(nil, []string{"seed"})
+
+	var bundle strings.Builder
+	if err := migrator.WriteUpBundle(&bundle, 0, 3); err != nil {
+		t.Fatal(err)
+	}
+	script := bundle.String()
+	if len(db.statements) != 0 {
+		t.Errorf("writing a bundle ran %q", db.statements)
+	}
+
+	if !strings.HasPrefix(script, "BEGIN;\n") || !strings.HasSuffix(script, "COMMIT;\n") {
+		t.Errorf("bundle isn't wrapped in a transaction:\n%s", script)
+	}
+	if !strings.Contains(script, "CREATE TABLE IF NOT EXISTS schema_migrations") {
+		t.Error("bundle doesn't create schema_migrations")
+	}
+	if strings.Contains(script, "CREATE TABLE beta") {
+		t.Error("bundle includes the skipped seed migration")
+	}
+	// Each migration is recorded right after its SQL
+	order := []string{"CREATE TABLE alpha", "VALUES (1, 'create_alpha'", "CREATE TABLE gamma", "VALUES (3, 'create_gamma'"}
+	position := -1
+	for _, part := range order {
+		next := strings.Index(script, part)
+		if next <= position {
+			t.Fatalf("%q is missing or out of order in:\n%s", part, script)
+		}
+		position = next
+	}
+
+	var down strings.Builder
+	if err := migrator.WriteDownBundle(&down, 3, 1); err != nil {
+		t.Fatal(err)
+	}
+	downScript := down.String()
+	gamma, beta := strings.Index(downScript, "DROP TABLE gamma"), strings.Index(downScript, "DROP TABLE beta")
+	if gamma < 0 || beta < gamma || strings.Contains(downScript, "DROP TABLE alpha") {
+		t.Errorf("down bundle doesn't revert 3 then 2:\n%s", downScript)
+	}
+	if !strings.Contains(downScript, "DELETE FROM schema_migrations WHERE version = 2;") {
+		t.Errorf("down bundle doesn't unrecord version 2:\n%s", downScript)
+	}
+
+	migrator.migrations[1].DownSQL = ""
+	if err := migrator.WriteDownBundle(&down, 3, 1); err == nil {
+		t.Error("expected an error for a migration without down SQL")
+	}
+}
+
+func TestBundleTerminatesStatements(t *testing.T) {
+	migrations := testMigrations()
+	migrations[0].UpSQL = "CREATE TABLE alpha (id INT)\n\n"
+	migrations[0].DownSQL = "DROP TABLE alpha"
+	migrator, _ := newTestMigrator(migrations)
+
+	var bundle strings.Builder
+	if err := migrator.WriteUpBundle(&bundle, 0, 3); err != nil {
+		t.Fatal(err)
+	}
+	script := bundle.String()
+	if !strings.Contains(script, "CREATE TABLE alpha (id INT);\nINSERT INTO schema_migrations") {
+		t.Errorf("unterminated up SQL runs into the INSERT:\n%s", script)
+	}
+	if strings.Contains(script, ";;") {
+		t.Errorf("terminated up SQL got a second semicolon:\n%s", script)
+	}
+
+	var down strings.Builder
+	if err := migrator.WriteDownBundle(&down, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(down.String(), "DROP TABLE alpha;\nDELETE FROM schema_migrations") {
+		t.Errorf("unterminated down SQL runs into the DELETE:\n%s", down.String())
+	}
+}
+
+// TestUpBundleRecordsVersions runs a bundle against a real database. It runs only when
+// TEST_POSTGRES_CONNECTION_STRING is set.
+func TestUpBundleRecordsVersions(t *testing.T) {
+	conn := scratchSchema(t)
+	migrator, _ := newTestMigrator(testMigrations())
+
+	var bundle strings.Builder
+	if err := migrator.WriteUpBundle(&bundle, 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec(bundle.String()); err != nil {
+		t.Fatalf("running the bundle failed: %v\n%s", err, bundle.String())
+	}
+	if got := recordedVersions(t, conn); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("recorded versions = %v, want [1 2]", got)
+	}
+
+	bundle.Reset()
+	if err := migrator.WriteDownBundle(&bundle, 2, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec(bundle.String()); err != nil {
+		t.Fatalf("running the down bundle failed: %v\n%s", err, bundle.String())
+	}
+	if got := recordedVersions(t, conn); len(got) != 0 {
+		t.Errorf("recorded versions after the down bundle = %v, want none", got)
+	}
+}
+
+func TestWriteInitScript(t *testing.T) {
+	migrator, _ := newTestMigrator(testMigrations())
+	var script strings.Builder
+	if err := migrator.WriteInitScript(&script); err != nil {
+		t.Fatal(err)
+	}
+	s := script.String()
+
+	guard := strings.Index(s, "schema_migrations is not empty")
+	if guard < strings.Index(s, "CREATE TABLE IF NOT EXISTS schema_migrations") || guard > strings.Index(s, "CREATE TABLE alpha") {
+		t.Errorf("guard doesn't run between initializing schema_migrations and the first migration:\n%s", s)
+	}
+	for _, want := range []string{"VALUES (1, 'create_alpha'", "VALUES (2, 'create_beta'", "VALUES (3, 'create_gamma'"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("init script doesn't record %s", want)
+		}
+	}
+}
+
+// TestInitScriptOnEmptyDatabase runs an init script twice against a real database. It runs only
+// when TEST_POSTGRES_CONNECTION_STRING is set.
+func TestInitScriptOnEmptyDatabase(t *testing.T) {
+	conn := scratchSchema(t)
+	migrator, _ := newTestMigrator(testMigrations())
+	var script strings.Builder
+	if err := migrator.WriteInitScript(&script); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := conn.Exec(script.String()); err != nil {
+		t.Fatalf("running the init script failed: %v\n%s", err, script.String())
+	}
+	if got := recordedVersions(t, conn); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("recorded versions = %v, want [1 2 3]", got)
+	}
+
+	// A second run is refused before it changes anything
+	if _, err := conn.Exec(script.String()); err == nil || !strings.Contains(err.Error(), "schema_migrations is not empty") {
+		t.Errorf("second run = %v, want the guard to abort it", err)
+	}
+	conn.Exec("ROLLBACK")
+	if got := recordedVersions(t, conn); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("recorded versions after the second run = %v, want [1 2 3]", got)
+	}
+}
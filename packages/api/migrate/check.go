@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// CheckRecorded compares the applied migrations recorded in schema_migrations with the loaded files
+// and returns a description of each mismatch: an applied version whose description differs from its
+// file's, or one with no file at all. Loaded migrations whose version directive disagrees with their
+// filename are reported first, whether or not they have been applied.
+func (m *Migrator) CheckRecorded() ([]string, error) {
+	mismatches := make([]string, 0)
+	for _, migration := range m.migrations {
+		if migration.DeclaredVersion != 0 && migration.DeclaredVersion != migration.Version {
+			mismatches = append(mismatches, fmt.Sprintf("migration %d (%s) declares version %d",
+				migration.Version, migration.Description, migration.DeclaredVersion))
+		}
+	}
+
+	rows, err := m.db.QueryContext(context.Background(), `
+        SELECT version, description
+        FROM schema_migrations
+        ORDER BY version
+    `)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	migrationsMap := make(map[int]*Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		migrationsMap[migration.Version] = migration
+	}
+
+	for rows.Next() {
+		var version int
+		var description string
+		if err := rows.Scan(&version, &description); err != nil {
+			return nil, err
+		}
+
+		migration, exists := migrationsMap[version]
+		if !exists {
+			mismatches = append(mismatches, fmt.Sprintf("applied migration %d (%s) has no file", version, description))
+			continue
+		}
+		if migration.Description != description {
+			mismatches = append(mismatches, fmt.Sprintf("migration %d was applied as %q but its file is named %q",
+				version, description, migration.Description))
+		}
+	}
+	return mismatches, rows.Err()
+}
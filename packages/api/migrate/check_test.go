@@ -0,0 +1,45 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckRecorded(t *testing.T) {
+	dir := writeMigrationFiles(t, map[string]string{
+		"001_create_alpha_up.sql": "CREATE TABLE alpha (id INT);",
+		"002_renamed_beta_up.sql": "-- +version 2\nCREATE TABLE beta (id INT);",
+		"003_create_gamma_up.sql": "-- +version 4\nCREATE TABLE gamma (id INT);",
+		"004_create_delta_up.sql": "CREATE TABLE delta (id INT);",
+	})
+	migrator, db := newTestMigrator(nil)
+	// A mismatched version directive doesn't stop the migrations from loading or applying
+	if err := migrator.LoadMigrations(dir); err != nil {
+		t.Fatalf("LoadMigrations: %v", err)
+	}
+	if migrator.migrations[2].DeclaredVersion != 4 {
+		t.Errorf("declared version = %d, want 4", migrator.migrations[2].DeclaredVersion)
+	}
+	if err := migrator.UpToVersion(3); err != nil {
+		t.Fatal(err)
+	}
+
+	// Migration 2 was applied under its old name, and migration 9 has since lost its file
+	record := db.applied[2]
+	record.description = "create_beta"
+	db.applied[2] = record
+	db.applied[9] = fakeRecord{version: 9, description: "dropped"}
+
+	mismatches, err := migrator.CheckRecorded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"migration 3 (create_gamma) declares version 4",
+		`migration 2 was applied as "create_beta" but its file is named "renamed_beta"`,
+		"applied migration 9 (dropped) has no file",
+	}
+	if !reflect.DeepEqual(mismatches, want) {
+		t.Errorf("CheckRecorded = %q, want %q", mismatches, want)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+)
+
+// Checksum returns the SHA-256 of the migration's up SQL, recorded when it is applied so later
+// edits to an applied migration file can be detected
+func (mg *Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(mg.UpSQL))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyChecksums compares the recorded checksums of migrations applied at or below upToVersion
+// with the loaded files and reports any that were modified after being applied. Migrations applied
+// before checksums were recorded have no checksum and are skipped. It returns the number of
+// applied migrations checked.
+func (m *Migrator) VerifyChecksums(upToVersion int) (int, error) {
+	rows, err := m.db.QueryContext(context.Background(), `
+        SELECT version, checksum
+        FROM schema_migrations
+        WHERE version <= $1
+        ORDER BY version
+    `, upToVersion)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	migrationsMap := make(map[int]*Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		migrationsMap[migration.Version] = migration
+	}
+
+	applied := 0
+	for rows.Next() {
+		var version int
+		var checksum sql.NullString
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return applied, err
+		}
+		applied++
+
+		migration, exists := migrationsMap[version]
+		if !exists || !checksum.Valid {
+			continue
+		}
+		if migration.Checksum() != checksum.String {
+			return applied, fmt.Errorf("migration %d (%s) has been modified since it was applied",
+				migration.Version, migration.Description)
+		}
+	}
+	return applied, rows.Err()
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyChecksums(t *testing.T) {
+	migrator, db := newTestMigrator(testMigrations())
+	if err := migrator.UpToVersion(2); err != nil {
+		t.Fatal(err)
+	}
+
+	checked, err := migrator.VerifyChecksums(2)
+	if err != nil || checked != 2 {
+		t.Errorf("VerifyChecksums = %d, %v, want 2 checked", checked, err)
+	}
+
+	// Rows applied before checksums were recorded are skipped
+	record := db.applied[1]
+	record.checksum = ""
+	db.applied[1] = record
+	if _, err := migrator.VerifyChecksums(2); err != nil {
+		t.Errorf("VerifyChecksums with an unrecorded checksum: %v", err)
+	}
+
+	// Editing an applied migration is caught, and blocks going up
+	migrator.migrations[1].UpSQL = "CREATE TABLE beta (id BIGINT);"
+	if _, err := migrator.VerifyChecksums(2); err == nil || !strings.Contains(err.Error(), "modified") {
+		t.Errorf("VerifyChecksums after an edit = %v, want a modified error", err)
+	}
+	if err := migrator.UpAll(); err == nil {
+		t.Error("UpAll applied migrations despite an edited applied migration")
+	}
+	if db.executed("CREATE TABLE gamma") {
+		t.Error("UpAll ran migration 3 after the checksum mismatch")
+	}
+
+	// Editing a pending migration is fine
+	migrator.migrations[1].UpSQL = "CREATE TABLE beta (id INT);"
+	migrator.migrations[2].UpSQL = "CREATE TABLE gamma (id BIGINT);"
+	if err := migrator.UpAll(); err != nil {
+		t.Errorf("UpAll after editing a pending migration: %v", err)
+	}
+}
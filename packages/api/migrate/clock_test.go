@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"crushingviz.info/api/types/acled"
+)
+
+func TestFixedClockPinsAppliedAt(t *testing.T) {
+	migrator, db := newTestMigrator(testMigrations())
+	appliedAt := time.Date(2024, time.March, 9, 12, 30, 0, 0, time.UTC)
+	migrator.SetClock(acled.FixedClock{Time: appliedAt})
+
+	if err := migrator.UpAll(); err != nil {
+		t.Fatalf("UpAll: %v", err)
+	}
+	for _, version := range db.versions() {
+		if got := db.applied[version].appliedAt; !got.Equal(appliedAt) {
+			t.Errorf("migration %d applied_at = %s, want %s", version, got, appliedAt)
+		}
+	}
+}
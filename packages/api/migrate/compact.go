@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// Compact replaces the schema_migrations rows of the versions a baseline migration squashes with
+// a single row for the baseline, so the history matches the migration files after a squash. The
+// baseline lists the versions it replaces with a `-- +squashes` directive. Compact refuses to run
+// unless every squashed version has been applied.
+func (m *Migrator) Compact(baselineVersion int) error {
+	ctx := context.Background()
+
+	var baseline *Migration
+	for _, migration := range m.migrations {
+		if migration.Version == baselineVersion {
+			baseline = migration
+			break
+		}
+	}
+	if baseline == nil {
+		return fmt.Errorf("baseline migration %d not found", baselineVersion)
+	}
+	if len(baseline.Squashes) == 0 {
+		return fmt.Errorf("migration %d does not declare the versions it squashes", baselineVersion)
+	}
+
+	err := m.Initialize()
+	if err != nil {
+		return err
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock the history so the check and the rewrite see the same rows
+	_, err = tx.ExecContext(ctx, "LOCK TABLE schema_migrations IN EXCLUSIVE MODE")
+	if err != nil {
+		return err
+	}
+
+	var applied int
+	err = tx.QueryRowContext(ctx, `
+        SELECT COUNT(*) FROM schema_migrations WHERE version = ANY($1)
+    `, pq.Array(baseline.Squashes)).Scan(&applied)
+	if err != nil {
+		return err
+	}
+	if applied != len(baseline.Squashes) {
+		err = fmt.Errorf("database has applied %d of the %d versions squashed by migration %d",
+			applied, len(baseline.Squashes), baselineVersion)
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM schema_migrations WHERE version = ANY($1)
+    `, pq.Array(baseline.Squashes))
+	if err != nil {
+		return fmt.Errorf("failed to remove squashed migration records: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO schema_migrations (version, description, applied_at, tags, checksum, applied_by)
+        VALUES ($1, $2, $3, $4, $5, COALESCE(NULLIF($6, ''), CURRENT_USER))
+        ON CONFLICT (version) DO UPDATE
+        SET description = EXCLUDED.description, tags = EXCLUDED.tags, checksum = EXCLUDED.checksum,
+            applied_by = EXCLUDED.applied_by
+    `, baseline.Version, baseline.Description, m.clock.Now(), pq.Array(baseline.Tags), baseline.Checksum(), m.appliedBy)
+	if err != nil {
+		return fmt.Errorf("failed to record baseline migration %d: %w", baseline.Version, err)
+	}
+
+	fmt.Printf("Compacted %d migrations into baseline %d: %s\n", len(baseline.Squashes), baseline.Version, baseline.Description)
+	return tx.Commit()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// squashedMigrations returns a baseline, version 10, squashing the three test migrations
+func squashedMigrations() []*Migration {
+	return []*Migration{{
+		Version:     10,
+		Description: "baseline",
+		UpSQL:       "CREATE TABLE alpha (id INT); CREATE TABLE beta (id INT); CREATE TABLE gamma (id INT);",
+		Squashes:    []int{1, 2, 3},
+	}}
+}
+
+func TestCompact(t *testing.T) {
+	migrator, db := newTestMigrator(testMigrations())
+	if err := migrator.UpAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	migrator.migrations = squashedMigrations()
+	migrator.SetAppliedBy("deploy-bot")
+	if err := migrator.Compact(10); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.versions(); !reflect.DeepEqual(got, []int{10}) {
+		t.Fatalf("versions after Compact = %v, want [10]", got)
+	}
+	if record := db.applied[10]; record.description != "baseline" || record.checksum != migrator.migrations[0].Checksum() {
+		t.Errorf("baseline record = %+v", record)
+	}
+	if record := db.applied[10]; record.appliedBy != "deploy-bot" {
+		t.Errorf("baseline applied_by = %q, want deploy-bot", record.appliedBy)
+	}
+	if !db.executed("LOCK TABLE schema_migrations") {
+		t.Error("Compact did not lock schema_migrations")
+	}
+
+	// The history now matches the files, so checksums verify and nothing is pending
+	if err := migrator.UpAll(); err != nil {
+		t.Errorf("UpAll after Compact: %v", err)
+	}
+}
+
+func TestCompactRefusesPartialHistory(t *testing.T) {
+	migrator, db := newTestMigrator(testMigrations())
+	if err := migrator.UpToVersion(2); err != nil {
+		t.Fatal(err)
+	}
+
+	migrator.migrations = squashedMigrations()
+	if err := migrator.Compact(10); err == nil {
+		t.Fatal("Compact succeeded with migration 3 unapplied")
+	}
+	if got := db.versions(); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("versions after a refused Compact = %v, want [1 2]", got)
+	}
+
+	if err := migrator.Compact(11); err == nil {
+		t.Error("Compact accepted an unknown baseline")
+	}
+	migrator.migrations[0].Squashes = nil
+	if err := migrator.Compact(10); err == nil {
+		t.Error("Compact accepted a baseline without a squashes directive")
+	}
+}
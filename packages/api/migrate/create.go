@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NamingScheme determines how the version of a new migration is chosen
+type NamingScheme string
+
+const (
+	// NamingSchemeInteger numbers migrations one past the highest loaded version, e.g. 003_add_index_up.sql
+	NamingSchemeInteger NamingScheme = "integer"
+	// NamingSchemeTimestamp numbers migrations by their UTC creation time, e.g. 20240102150405_add_index_up.sql,
+	// which avoids version collisions between branches
+	NamingSchemeTimestamp NamingScheme = "timestamp"
+)
+
+// minTimestampVersion is the smallest 14 digit (YYYYMMDDHHMMSS) version, far beyond any integer
+// version
+const minTimestampVersion = 10000000000000
+
+// checkRecordable reports whether a version can be recorded in schema_migrations. Any int fits its
+// BIGINT version column, but version 0 can't be told apart from an empty table, since
+// GetCurrentVersion reports 0 when nothing is applied.
+func checkRecordable(version int) error {
+	if version <= 0 {
+		return errors.New("versions must be positive to be recorded in schema_migrations")
+	}
+	return nil
+}
+
+// nonDescriptionChars matches runs of characters not allowed in a migration description
+var nonDescriptionChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CreateMigration writes empty up and down files for a new migration into outDir, which may differ
+// from the directory migrations are loaded from. It returns the paths of the created files.
+func (m *Migrator) CreateMigration(outDir string, description string, scheme NamingScheme, now time.Time) ([]string, error) {
+	description = strings.Trim(nonDescriptionChars.ReplaceAllString(strings.ToLower(description), "_"), "_")
+	if description == "" {
+		return nil, errors.New("migration description is required")
+	}
+
+	var version string
+	switch scheme {
+	case NamingSchemeInteger:
+		next := 1
+		if len(m.migrations) > 0 {
+			highest := m.migrations[len(m.migrations)-1]
+			if highest.Version >= minTimestampVersion {
+				return nil, fmt.Errorf("migration %d (%s) uses a timestamp version, so new migrations must use the %q scheme",
+					highest.Version, highest.Description, NamingSchemeTimestamp)
+			}
+			next = highest.Version + 1
+		}
+		version = fmt.Sprintf("%03d", next)
+	case NamingSchemeTimestamp:
+		version = now.UTC().Format("20060102150405")
+	default:
+		return nil, fmt.Errorf("unknown naming scheme %q", scheme)
+	}
+
+	// Make sure the loader will accept what we are about to write, and that it can be recorded
+	wantVersion, err := strconv.Atoi(version)
+	if err != nil {
+		return nil, fmt.Errorf("naming scheme %q produced an invalid version %q", scheme, version)
+	}
+	if err := checkRecordable(wantVersion); err != nil {
+		return nil, fmt.Errorf("naming scheme %q produced version %d: %w", scheme, wantVersion, err)
+	}
+	for _, migration := range m.migrations {
+		if migration.Version == wantVersion {
+			return nil, fmt.Errorf("migration version %d already exists", wantVersion)
+		}
+	}
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, 2)
+	for _, direction := range []string{"up", "down"} {
+		fileName := fmt.Sprintf("%s_%s_%s.sql", version, description, direction)
+		parsedVersion, parsedDescription, _, err := parseMigrationFilename(fileName)
+		if err != nil || parsedVersion != wantVersion || parsedDescription != description {
+			return nil, fmt.Errorf("generated filename %s would not load as version %d", fileName, wantVersion)
+		}
+
+		path := filepath.Join(outDir, fileName)
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return nil, err
+		}
+		_, err = fmt.Fprintf(file, "-- %s migration for %s\n", strings.ToUpper(direction[:1])+direction[1:], description)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
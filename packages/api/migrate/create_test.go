@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateMigrationInteger(t *testing.T) {
+	migrator, _ := newTestMigrator(testMigrations())
+	dir := t.TempDir()
+
+	paths, err := migrator.CreateMigration(dir, "Add Event Index!", NamingSchemeInteger, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "004_add_event_index_up.sql"), filepath.Join(dir, "004_add_event_index_down.sql")}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("CreateMigration = %v, want %v", paths, want)
+	}
+	content, err := os.ReadFile(paths[0])
+	if err != nil || string(content) != "-- Up migration for add_event_index\n" {
+		t.Errorf("up file = %q, %v", content, err)
+	}
+
+	empty, _ := newTestMigrator(nil)
+	paths, err = empty.CreateMigration(t.TempDir(), "init", NamingSchemeInteger, time.Now())
+	if err != nil || filepath.Base(paths[0]) != "001_init_up.sql" {
+		t.Errorf("first migration = %v, %v, want 001_init_up.sql", paths, err)
+	}
+}
+
+func TestCreateMigrationTimestampIsRecordable(t *testing.T) {
+	dir := t.TempDir()
+	created := time.Date(2024, time.January, 2, 15, 4, 5, 0, time.FixedZone("CET", 3600))
+
+	migrator, db := newTestMigrator(nil)
+	paths, err := migrator.CreateMigration(dir, "add event index", NamingSchemeTimestamp, created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := filepath.Base(paths[0]); got != "20240102140405_add_event_index_up.sql" {
+		t.Errorf("up file = %s, want the UTC timestamp 20240102140405", got)
+	}
+
+	// The created files load and are recorded under the timestamp version
+	if err := migrator.LoadMigrations(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.UpAll(); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.versions(); !reflect.DeepEqual(got, []int{20240102140405}) {
+		t.Errorf("recorded versions = %v, want [20240102140405]", got)
+	}
+}
+
+func TestCreateMigrationRejectsIntegerAfterTimestamp(t *testing.T) {
+	migrations := append(testMigrations(), &Migration{Version: 20240102140405, Description: "add_event_index"})
+	migrator, _ := newTestMigrator(migrations)
+
+	_, err := migrator.CreateMigration(t.TempDir(), "next", NamingSchemeInteger, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "timestamp") {
+		t.Errorf("CreateMigration = %v, want an error about the timestamp scheme", err)
+	}
+	if _, err := migrator.CreateMigration(t.TempDir(), "next", NamingSchemeTimestamp, time.Now()); err != nil {
+		t.Errorf("timestamp scheme after a timestamp version: %v", err)
+	}
+}
+
+func TestCreateMigrationRejectsInvalidInput(t *testing.T) {
+	migrator, _ := newTestMigrator(testMigrations())
+	if _, err := migrator.CreateMigration(t.TempDir(), "!!!", NamingSchemeInteger, time.Now()); err == nil {
+		t.Error("CreateMigration accepted an empty description")
+	}
+	if _, err := migrator.CreateMigration(t.TempDir(), "next", NamingScheme("uuid"), time.Now()); err == nil {
+		t.Error("CreateMigration accepted an unknown scheme")
+	}
+	if err := checkRecordable(0); err == nil {
+		t.Error("version 0 is indistinguishable from an empty schema_migrations but was accepted")
+	}
+}
+
+func TestInitializeWidensVersionColumn(t *testing.T) {
+	if !strings.Contains(initializeSQL, "version BIGINT PRIMARY KEY") {
+		t.Error("schema_migrations is not created with a BIGINT version")
+	}
+	if !strings.Contains(initializeSQL, "ALTER COLUMN version TYPE BIGINT") {
+		t.Error("existing INT version columns are not widened")
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DataDump writes the rows of a table to w as re-runnable INSERT statements, without any schema DDL.
+// The table may be schema-qualified, e.g. public.acled_event.
+// where is an optional SQL condition used to select rows, and a limit of 0 dumps every matching row.
+func (m *Migrator) DataDump(w io.Writer, table string, where string, limit int) error {
+	query := "SELECT * FROM " + quoteQualifiedName(table)
+	if where != "" {
+		query += " WHERE " + where
+	}
+	if limit > 0 {
+		query += " LIMIT " + strconv.Itoa(limit)
+	}
+
+	rows, err := m.db.QueryContext(context.Background(), query)
+	if err != nil {
+		return fmt.Errorf("failed to query %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	quotedColumns := make([]string, len(columns))
+	for i, column := range columns {
+		quotedColumns[i] = pq.QuoteIdentifier(column)
+	}
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES (",
+		quoteQualifiedName(table), strings.Join(quotedColumns, ", "))
+
+	// lib/pq returns the types it doesn't decode (numeric, uuid, json, arrays...) as their text
+	// form in a []byte, so when the column types are known only bytea columns are dumped as binary
+	textColumns := make([]bool, len(columns))
+	if typed, ok := rows.(interface {
+		ColumnTypes() ([]*sql.ColumnType, error)
+	}); ok {
+		columnTypes, err := typed.ColumnTypes()
+		if err != nil {
+			return err
+		}
+		for i, columnType := range columnTypes {
+			textColumns[i] = columnType.DatabaseTypeName() != "BYTEA"
+		}
+	}
+
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	literals := make([]string, len(columns))
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+		for i, value := range values {
+			if b, ok := value.([]byte); ok && textColumns[i] {
+				value = string(b)
+			}
+			literal, err := sqlLiteral(value)
+			if err != nil {
+				return fmt.Errorf("column %s: %w", columns[i], err)
+			}
+			literals[i] = literal
+		}
+		if _, err := fmt.Fprintf(w, "%s%s);\n", prefix, strings.Join(literals, ", ")); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// sqlLiteral renders a scanned value as a SQL literal. Strings are single-quoted with embedded
+// quotes doubled, which is safe with standard_conforming_strings (the default since Postgres 9.1).
+// Bytes are written as a hex bytea literal.
+func sqlLiteral(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return quoteString(strconv.FormatFloat(v, 'g', -1, 64)), nil
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case time.Time:
+		return quoteString(v.Format(time.RFC3339Nano)), nil
+	case []byte:
+		return "'\\x" + hex.EncodeToString(v) + "'::bytea", nil
+	case string:
+		return quoteString(v), nil
+	}
+	return "", fmt.Errorf("unsupported value type %T", value)
+}
+
+// quoteString single-quotes a string for use as a SQL literal
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DB is the subset of database operations the Migrator needs. *sql.DB is adapted to it by
+// NewMigrator, and other drivers (e.g. a pgx pool) can be used by implementing it directly.
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) Row
+	QueryContext(ctx context.Context, query string, args ...any) (Rows, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error)
+}
+
+// Row is a single result row, satisfied by *sql.Row
+type Row interface {
+	Scan(dest ...any) error
+}
+
+// Rows is an iterator over a result set, satisfied by *sql.Rows
+type Rows interface {
+	Next() bool
+	Columns() ([]string, error)
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+// Tx is a database transaction
+type Tx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) Row
+	Commit() error
+	Rollback() error
+}
+
+// sqlDB adapts a *sql.DB to the DB interface
+type sqlDB struct {
+	db *sql.DB
+}
+
+func (s sqlDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return s.db.ExecContext(ctx, query, args...)
+}
+
+func (s sqlDB) QueryRowContext(ctx context.Context, query string, args ...any) Row {
+	return s.db.QueryRowContext(ctx, query, args...)
+}
+
+func (s sqlDB) QueryContext(ctx context.Context, query string, args ...any) (Rows, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (s sqlDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	tx, err := s.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return sqlTx{tx: tx}, nil
+}
+
+// sqlTx adapts a *sql.Tx to the Tx interface
+type sqlTx struct {
+	tx *sql.Tx
+}
+
+func (s sqlTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return s.tx.ExecContext(ctx, query, args...)
+}
+
+func (s sqlTx) QueryRowContext(ctx context.Context, query string, args ...any) Row {
+	return s.tx.QueryRowContext(ctx, query, args...)
+}
+
+func (s sqlTx) Commit() error {
+	return s.tx.Commit()
+}
+
+func (s sqlTx) Rollback() error {
+	return s.tx.Rollback()
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// directivePrefix marks a comment line in a migration's up SQL that configures the migration,
+// e.g. `-- +tags schema,seed`
+const directivePrefix = "-- +"
+
+// parseDirectives collects the `-- +name value` comment lines from a migration's SQL
+func parseDirectives(sql string) map[string]string {
+	directives := make(map[string]string)
+	for _, line := range strings.Split(sql, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, directivePrefix) {
+			continue
+		}
+		name, value, _ := strings.Cut(strings.TrimPrefix(line, directivePrefix), " ")
+		directives[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return directives
+}
+
+// tableNamePattern matches a plain or schema-qualified table name
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// applyDirectives configures the migration from the directives found in its up SQL
+func (mg *Migration) applyDirectives(directives map[string]string) error {
+	for name, value := range directives {
+		switch name {
+		case "version":
+			version, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid version directive %q in migration %d", value, mg.Version)
+			}
+			// A mismatch is reported by CheckRecorded rather than failing the load
+			mg.DeclaredVersion = version
+		case "tags":
+			mg.Tags = splitList(value)
+		case "locktimeout":
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid locktimeout %q in migration %d: %w", value, mg.Version, err)
+			}
+			if timeout < time.Millisecond {
+				return fmt.Errorf("locktimeout must be at least 1ms in migration %d", mg.Version)
+			}
+			mg.LockTimeout = timeout
+		case "minpgversion":
+			version, err := strconv.Atoi(value)
+			if err != nil || version < 1 {
+				return fmt.Errorf("invalid minpgversion %q in migration %d", value, mg.Version)
+			}
+			mg.MinPGVersion = version
+		case "squashes":
+			for _, item := range splitList(value) {
+				version, err := strconv.Atoi(item)
+				if err != nil || version < 1 {
+					return fmt.Errorf("invalid squashed version %q in migration %d", item, mg.Version)
+				}
+				mg.Squashes = append(mg.Squashes, version)
+			}
+		case "analyze":
+			for _, table := range splitList(value) {
+				if !tableNamePattern.MatchString(table) {
+					return fmt.Errorf("invalid table name %q to analyze in migration %d", table, mg.Version)
+				}
+				mg.AnalyzeTables = append(mg.AnalyzeTables, table)
+			}
+		default:
+			return fmt.Errorf("unknown directive %q in migration %d", name, mg.Version)
+		}
+	}
+	return nil
+}
+
+// splitList splits a comma separated list, trimming whitespace and dropping empty entries
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,532 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+
+	"crushingviz.info/api/types/acled"
+)
+
+// fakeRecord is a row of the fake schema_migrations table
+type fakeRecord struct {
+	version     int
+	description string
+	appliedAt   time.Time
+	tags        []string
+	checksum    string
+	appliedBy   string
+}
+
+// fakeArea is a row of the fake geographic_area table
+type fakeArea struct {
+	id        int
+	acledCode *int
+	name      string
+	areaType  acled.GeographicAreaType
+	iso       *string
+	parentID  *int
+	geoJSON   []byte
+}
+
+// fakeResult is a canned result set returned for any query containing its key
+type fakeResult struct {
+	columns []string
+	rows    [][]any
+}
+
+// fakeDB is an in-memory stand-in for Postgres. It understands the statements the Migrator issues
+// against schema_migrations, geographic_area and acled_weekly_agg, records every statement it is
+// asked to run, and answers other queries from canned results. Areas and aggregates are written
+// straight to their tables, so they stay even if their transaction is rolled back.
+type fakeDB struct {
+	initialized bool
+	applied     map[int]fakeRecord
+	// statements is every statement executed or queried, including BEGIN, COMMIT and ROLLBACK, in order
+	statements []string
+	// serverVersionNum is the answer to SHOW server_version_num
+	serverVersionNum string
+	// failOn makes any statement containing it fail
+	failOn string
+	// results are canned answers for queries containing the key
+	results map[string]fakeResult
+	// areas are the rows of geographic_area, in insertion order
+	areas []fakeArea
+	// aggregates are the rows of acled_weekly_agg
+	aggregates []acled.ACLEDWeeklyAggregate
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{
+		applied:          make(map[int]fakeRecord),
+		serverVersionNum: "160002",
+		results:          make(map[string]fakeResult),
+	}
+}
+
+// versions returns the applied versions in order
+func (f *fakeDB) versions() []int {
+	versions := make([]int, 0, len(f.applied))
+	for version := range f.applied {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// executed reports whether a statement containing substr was executed or queried
+func (f *fakeDB) executed(substr string) bool {
+	return f.indexOf(substr) >= 0
+}
+
+// indexOf returns the position of the first statement containing substr, or -1
+func (f *fakeDB) indexOf(substr string) int {
+	for i, statement := range f.statements {
+		if strings.Contains(statement, substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *fakeDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return f.exec(f.applied, query, args)
+}
+
+func (f *fakeDB) QueryRowContext(ctx context.Context, query string, args ...any) Row {
+	return f.queryRow(f.applied, query, args)
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (Rows, error) {
+	return f.query(f.applied, query, args)
+}
+
+func (f *fakeDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	f.statements = append(f.statements, "BEGIN")
+	applied := make(map[int]fakeRecord, len(f.applied))
+	for version, record := range f.applied {
+		applied[version] = record
+	}
+	return &fakeTx{db: f, applied: applied}, nil
+}
+
+func (f *fakeDB) exec(applied map[int]fakeRecord, query string, args []any) (sql.Result, error) {
+	f.statements = append(f.statements, query)
+	if f.failOn != "" && strings.Contains(query, f.failOn) {
+		return nil, fmt.Errorf("fake failure executing %q", f.failOn)
+	}
+
+	switch {
+	case query == initializeSQL:
+		f.initialized = true
+	case strings.Contains(query, "INSERT INTO schema_migrations"):
+		record := fakeRecord{
+			version:     args[0].(int),
+			description: args[1].(string),
+			appliedAt:   args[2].(time.Time),
+			tags:        []string(*args[3].(*pq.StringArray)),
+			checksum:    args[4].(string),
+			appliedBy:   "postgres",
+		}
+		if len(args) > 5 && args[5].(string) != "" {
+			record.appliedBy = args[5].(string)
+		}
+		if _, exists := applied[record.version]; exists && !strings.Contains(query, "ON CONFLICT") {
+			return nil, fmt.Errorf("duplicate key value: version %d", record.version)
+		}
+		applied[record.version] = record
+	case strings.Contains(query, "DELETE FROM schema_migrations"):
+		for _, version := range fakeVersionArgs(query, args) {
+			delete(applied, version)
+		}
+	case strings.Contains(query, "UPDATE acled_weekly_agg SET disorder_type"):
+		disorderType, eventType, limit := acled.DisorderType(args[0].(string)), acled.EventType(args[1].(string)), args[2].(int)
+		updated := 0
+		for i := range f.aggregates {
+			row := &f.aggregates[i]
+			if updated < limit && row.EventType == eventType && row.DisorderType != disorderType {
+				row.DisorderType = disorderType
+				updated++
+			}
+		}
+		return driverResult(updated), nil
+	}
+	return driverResult(1), nil
+}
+
+func (f *fakeDB) queryRow(applied map[int]fakeRecord, query string, args []any) Row {
+	f.statements = append(f.statements, query)
+	if f.failOn != "" && strings.Contains(query, f.failOn) {
+		return fakeRow{err: fmt.Errorf("fake failure querying %q", f.failOn)}
+	}
+	if result, ok := f.cannedResult(query); ok {
+		if len(result.rows) == 0 {
+			return fakeRow{err: sql.ErrNoRows}
+		}
+		return fakeRow{values: result.rows[0]}
+	}
+
+	switch {
+	case strings.Contains(query, "INSERT INTO geographic_area"):
+		area := fakeArea{
+			id:        len(f.areas) + 1,
+			acledCode: args[0].(*int),
+			name:      args[1].(string),
+			areaType:  args[2].(acled.GeographicAreaType),
+			iso:       args[3].(*string),
+			parentID:  args[4].(*int),
+		}
+		if geoJSON, ok := args[5].([]byte); ok {
+			area.geoJSON = geoJSON
+		}
+		f.areas = append(f.areas, area)
+		return fakeRow{values: []any{area.id}}
+	case strings.Contains(query, "SELECT id FROM geographic_area WHERE type = $1 AND name = $2"):
+		for _, area := range f.areas {
+			if string(area.areaType) == fmt.Sprint(args[0]) && area.name == fmt.Sprint(args[1]) {
+				return fakeRow{values: []any{area.id}}
+			}
+		}
+		return fakeRow{err: sql.ErrNoRows}
+	case strings.Contains(query, "COALESCE(MAX(version), 0)"):
+		if !f.initialized {
+			return fakeRow{err: errors.New(`relation "schema_migrations" does not exist`)}
+		}
+		highest := 0
+		for version := range applied {
+			highest = max(highest, version)
+		}
+		return fakeRow{values: []any{highest}}
+	case strings.Contains(query, "SHOW server_version_num"):
+		return fakeRow{values: []any{f.serverVersionNum}}
+	case strings.Contains(query, "SELECT COUNT(*) FROM schema_migrations"):
+		count := 0
+		for _, version := range fakeVersionArgs(query, args) {
+			if _, exists := applied[version]; exists {
+				count++
+			}
+		}
+		return fakeRow{values: []any{count}}
+	}
+	return fakeRow{err: fmt.Errorf("fake database cannot answer %q", query)}
+}
+
+func (f *fakeDB) query(applied map[int]fakeRecord, query string, args []any) (Rows, error) {
+	f.statements = append(f.statements, query)
+	if f.failOn != "" && strings.Contains(query, f.failOn) {
+		return nil, fmt.Errorf("fake failure querying %q", f.failOn)
+	}
+	if result, ok := f.cannedResult(query); ok {
+		return &fakeRows{columns: result.columns, rows: result.rows}, nil
+	}
+	if strings.Contains(query, "FROM geographic_area a") {
+		return f.exportAreas(), nil
+	}
+	if !strings.Contains(query, "FROM schema_migrations") {
+		return nil, fmt.Errorf("fake database cannot answer %q", query)
+	}
+	if !f.initialized {
+		return nil, errors.New(`relation "schema_migrations" does not exist`)
+	}
+
+	records := make([]fakeRecord, 0, len(applied))
+	for _, record := range applied {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].version < records[j].version })
+
+	rows := &fakeRows{}
+	switch {
+	case strings.Contains(query, "SELECT version, checksum"):
+		rows.columns = []string{"version", "checksum"}
+		for _, record := range records {
+			if record.version <= args[0].(int) {
+				var checksum any
+				if record.checksum != "" {
+					checksum = record.checksum
+				}
+				rows.rows = append(rows.rows, []any{record.version, checksum})
+			}
+		}
+	case strings.Contains(query, "SELECT version, description, applied_at"):
+		rows.columns = []string{"version", "description", "applied_at", "applied_by"}
+		for _, record := range records {
+			rows.rows = append(rows.rows, []any{record.version, record.description, record.appliedAt, record.appliedBy})
+		}
+	case strings.Contains(query, "WHERE version > $1"):
+		rows.columns = []string{"version", "description"}
+		for i := len(records) - 1; i >= 0; i-- {
+			if records[i].version > args[0].(int) {
+				rows.rows = append(rows.rows, []any{records[i].version, records[i].description})
+			}
+		}
+	case strings.Contains(query, "SELECT version, description"):
+		rows.columns = []string{"version", "description"}
+		for _, record := range records {
+			rows.rows = append(rows.rows, []any{record.version, record.description})
+		}
+	default:
+		return nil, fmt.Errorf("fake database cannot answer %q", query)
+	}
+	return rows, nil
+}
+
+// exportAreas answers the ExportAreas query: every area with its parent's type and name, regions
+// first, then countries, then admin1s, each by name
+func (f *fakeDB) exportAreas() *fakeRows {
+	byID := make(map[int]fakeArea, len(f.areas))
+	for _, area := range f.areas {
+		byID[area.id] = area
+	}
+	areas := append([]fakeArea(nil), f.areas...)
+	sort.SliceStable(areas, func(i, j int) bool {
+		if areaTypeRank[areas[i].areaType] != areaTypeRank[areas[j].areaType] {
+			return areaTypeRank[areas[i].areaType] < areaTypeRank[areas[j].areaType]
+		}
+		return areas[i].name < areas[j].name
+	})
+
+	rows := &fakeRows{columns: []string{"acled_code", "name", "type", "iso", "parent_type", "parent_name", "geojson"}}
+	for _, area := range areas {
+		var acledCode, iso, geoJSON any
+		if area.acledCode != nil {
+			acledCode = int64(*area.acledCode)
+		}
+		if area.iso != nil {
+			iso = *area.iso
+		}
+		if area.geoJSON != nil {
+			geoJSON = area.geoJSON
+		}
+		var parentType, parentName string
+		if area.parentID != nil {
+			parent := byID[*area.parentID]
+			parentType, parentName = string(parent.areaType), parent.name
+		}
+		rows.rows = append(rows.rows, []any{acledCode, area.name, string(area.areaType), iso, parentType, parentName, geoJSON})
+	}
+	return rows
+}
+
+// cannedResult finds a canned result whose key the query contains
+func (f *fakeDB) cannedResult(query string) (fakeResult, bool) {
+	for key, result := range f.results {
+		if strings.Contains(query, key) {
+			return result, true
+		}
+	}
+	return fakeResult{}, false
+}
+
+// fakeVersionArgs returns the versions a schema_migrations statement filters on, either a single
+// version = $1 or a version = ANY($1) array
+func fakeVersionArgs(query string, args []any) []int {
+	if len(args) == 0 {
+		return nil
+	}
+	if array, ok := args[0].(pq.GenericArray); ok {
+		return array.A.([]int)
+	}
+	return []int{args[0].(int)}
+}
+
+// fakeTx buffers changes to schema_migrations until it is committed
+type fakeTx struct {
+	db      *fakeDB
+	applied map[int]fakeRecord
+	done    bool
+}
+
+func (t *fakeTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return t.db.exec(t.applied, query, args)
+}
+
+func (t *fakeTx) QueryRowContext(ctx context.Context, query string, args ...any) Row {
+	return t.db.queryRow(t.applied, query, args)
+}
+
+func (t *fakeTx) Commit() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	t.db.statements = append(t.db.statements, "COMMIT")
+	t.db.applied = t.applied
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	t.db.statements = append(t.db.statements, "ROLLBACK")
+	return nil
+}
+
+// driverResult is the sql.Result of a fake statement
+type driverResult int64
+
+func (r driverResult) LastInsertId() (int64, error) { return 0, errors.New("not supported") }
+func (r driverResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+type fakeRow struct {
+	values []any
+	err    error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	return scanValues(r.values, dest)
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]any
+	next    int
+	closed  bool
+}
+
+func (r *fakeRows) Next() bool {
+	if r.closed || r.next >= len(r.rows) {
+		return false
+	}
+	r.next++
+	return true
+}
+
+func (r *fakeRows) Columns() ([]string, error) { return r.columns, nil }
+func (r *fakeRows) Err() error                 { return nil }
+
+func (r *fakeRows) Close() error {
+	r.closed = true
+	return nil
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	if r.next == 0 {
+		return errors.New("Scan called without calling Next")
+	}
+	return scanValues(r.rows[r.next-1], dest)
+}
+
+// scanValues assigns values to scan destinations the way database/sql does for the types the
+// migrator scans into
+func scanValues(values []any, dest []any) error {
+	if len(values) != len(dest) {
+		return fmt.Errorf("expected %d destination arguments in Scan, not %d", len(values), len(dest))
+	}
+	for i, value := range values {
+		if scanner, ok := dest[i].(sql.Scanner); ok {
+			if err := scanner.Scan(value); err != nil {
+				return err
+			}
+			continue
+		}
+		target := reflect.ValueOf(dest[i]).Elem()
+		if value == nil {
+			target.Set(reflect.Zero(target.Type()))
+			continue
+		}
+		source := reflect.ValueOf(value)
+		if target.Kind() != reflect.Interface && source.Kind() != target.Kind() {
+			return fmt.Errorf("cannot scan %T into %T", value, dest[i])
+		}
+		target.Set(source.Convert(target.Type()))
+	}
+	return nil
+}
+
+// testMigrations returns three migrations creating and dropping a table each
+func testMigrations() []*Migration {
+	migrations := make([]*Migration, 0, 3)
+	for version, table := range []string{"alpha", "beta", "gamma"} {
+		migrations = append(migrations, &Migration{
+			Version:     version + 1,
+			Description: "create_" + table,
+			UpSQL:       "CREATE TABLE " + table + " (id INT);",
+			DownSQL:     "DROP TABLE " + table + ";",
+		})
+	}
+	return migrations
+}
+
+// newTestMigrator returns a migrator over a fake database with the given migrations loaded
+func newTestMigrator(migrations []*Migration) (*Migrator, *fakeDB) {
+	db := newFakeDB()
+	migrator := NewMigratorWithDB(db)
+	migrator.migrations = migrations
+	return migrator, db
+}
+
+func TestUpAndDownWithFakeDB(t *testing.T) {
+	migrator, db := newTestMigrator(testMigrations())
+
+	if err := migrator.UpAll(); err != nil {
+		t.Fatalf("UpAll: %v", err)
+	}
+	if got := db.versions(); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Fatalf("applied versions after UpAll = %v, want [1 2 3]", got)
+	}
+	for _, table := range []string{"alpha", "beta", "gamma"} {
+		if !db.executed("CREATE TABLE " + table) {
+			t.Errorf("UpAll did not create %s", table)
+		}
+	}
+	if record := db.applied[2]; record.description != "create_beta" || record.checksum != migrator.migrations[1].Checksum() {
+		t.Errorf("recorded migration 2 = %+v", record)
+	}
+
+	// Running again is a no-op
+	statements := len(db.statements)
+	if err := migrator.UpAll(); err != nil {
+		t.Fatalf("second UpAll: %v", err)
+	}
+	for _, statement := range db.statements[statements:] {
+		if statement == "BEGIN" {
+			t.Error("second UpAll opened a transaction with nothing to apply")
+		}
+	}
+
+	if err := migrator.Down(); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if got := db.versions(); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Fatalf("applied versions after Down = %v, want [1 2]", got)
+	}
+
+	if err := migrator.DownToVersion(0); err != nil {
+		t.Fatalf("DownToVersion(0): %v", err)
+	}
+	if got := db.versions(); len(got) != 0 {
+		t.Fatalf("applied versions after DownToVersion(0) = %v, want none", got)
+	}
+	if db.indexOf("DROP TABLE beta") > db.indexOf("DROP TABLE alpha") {
+		t.Error("migrations were not reverted newest first")
+	}
+}
+
+func TestUpRollsBackOnFailure(t *testing.T) {
+	migrator, db := newTestMigrator(testMigrations())
+	db.failOn = "CREATE TABLE gamma"
+
+	if err := migrator.UpAll(); err == nil {
+		t.Fatal("UpAll succeeded despite a failing migration")
+	}
+	if got := db.versions(); len(got) != 0 {
+		t.Errorf("applied versions after a failed run = %v, want none", got)
+	}
+	if !db.executed("ROLLBACK") {
+		t.Error("failed run did not roll back")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// HistoryEntry is one applied migration as recorded in schema_migrations
+type HistoryEntry struct {
+	Version     int       `json:"version"`
+	Description string    `json:"description"`
+	AppliedAt   time.Time `json:"applied_at"`
+	AppliedBy   string    `json:"applied_by"`
+}
+
+// SetAppliedBy sets who is recorded as applying migrations. When empty the database user is recorded.
+func (m *Migrator) SetAppliedBy(appliedBy string) {
+	m.appliedBy = appliedBy
+}
+
+// History returns the applied migrations in version order
+func (m *Migrator) History() ([]HistoryEntry, error) {
+	rows, err := m.db.QueryContext(context.Background(), `
+        SELECT version, description, applied_at, COALESCE(applied_by, '')
+        FROM schema_migrations
+        ORDER BY version
+    `)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]HistoryEntry, 0)
+	for rows.Next() {
+		var entry HistoryEntry
+		if err := rows.Scan(&entry.Version, &entry.Description, &entry.AppliedAt, &entry.AppliedBy); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// WriteHistory writes history entries to w as "text", "csv" or "json"
+func WriteHistory(w io.Writer, entries []HistoryEntry, format string) error {
+	switch format {
+	case "", "text":
+		for _, entry := range entries {
+			if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", entry.Version, entry.AppliedAt.Format(time.RFC3339),
+				entry.AppliedBy, entry.Description); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"version", "description", "applied_at", "applied_by"})
+		for _, entry := range entries {
+			cw.Write([]string{strconv.Itoa(entry.Version), entry.Description,
+				entry.AppliedAt.Format(time.RFC3339), entry.AppliedBy})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+	return fmt.Errorf("unknown history format %q (expected text, csv or json)", format)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"crushingviz.info/api/types/acled"
+)
+
+func TestHistory(t *testing.T) {
+	migrator, _ := newTestMigrator(testMigrations())
+	appliedAt := time.Date(2024, time.March, 9, 12, 0, 0, 0, time.UTC)
+	migrator.SetClock(acled.FixedClock{Time: appliedAt})
+	if err := migrator.UpToVersion(1); err != nil {
+		t.Fatal(err)
+	}
+	migrator.SetAppliedBy("deploy-bot")
+	if err := migrator.UpAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := migrator.History()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []HistoryEntry{
+		{Version: 1, Description: "create_alpha", AppliedAt: appliedAt, AppliedBy: "postgres"},
+		{Version: 2, Description: "create_beta", AppliedAt: appliedAt, AppliedBy: "deploy-bot"},
+		{Version: 3, Description: "create_gamma", AppliedAt: appliedAt, AppliedBy: "deploy-bot"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("History = %+v, want %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestWriteHistory(t *testing.T) {
+	entries := []HistoryEntry{{
+		Version: 2, Description: "create_beta",
+		AppliedAt: time.Date(2024, time.March, 9, 12, 0, 0, 0, time.UTC), AppliedBy: "deploy, bot",
+	}}
+
+	var text strings.Builder
+	if err := WriteHistory(&text, entries, "text"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "2\t2024-03-09T12:00:00Z\tdeploy, bot\tcreate_beta\n"; text.String() != want {
+		t.Errorf("text = %q, want %q", text.String(), want)
+	}
+
+	var csv strings.Builder
+	if err := WriteHistory(&csv, entries, "csv"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "version,description,applied_at,applied_by\n2,create_beta,2024-03-09T12:00:00Z,\"deploy, bot\"\n"; csv.String() != want {
+		t.Errorf("csv = %q, want %q", csv.String(), want)
+	}
+
+	var out strings.Builder
+	if err := WriteHistory(&out, entries, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []HistoryEntry
+	if err := json.Unmarshal([]byte(out.String()), &decoded); err != nil || len(decoded) != 1 || decoded[0] != entries[0] {
+		t.Errorf("json = %s (%v), want the entries back", out.String(), err)
+	}
+
+	if err := WriteHistory(&out, entries, "yaml"); err == nil {
+		t.Error("WriteHistory accepted an unknown format")
+	}
+}
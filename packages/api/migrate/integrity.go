@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// integritySampleSize is how many offending rows are reported per check
+const integritySampleSize = 5
+
+// IntegrityIssue is a data integrity check that found offending rows
+type IntegrityIssue struct {
+	Check   string
+	Count   int
+	Samples []string
+}
+
+// integrityChecks are queries returning one descriptive line per offending row
+var integrityChecks = []struct {
+	name  string
+	query string
+}{
+	{
+		name: "aggregates referencing a missing area",
+		query: `
+        SELECT format('week %s region %s country %s admin1 %s %s', w.week::date, w.region_id, w.country_id, w.admin1_id, w.sub_event_type)
+        FROM acled_weekly_agg w
+        WHERE NOT EXISTS (SELECT 1 FROM geographic_area a WHERE a.id = w.region_id)
+           OR (w.country_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM geographic_area a WHERE a.id = w.country_id))
+           OR (w.admin1_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM geographic_area a WHERE a.id = w.admin1_id))`,
+	},
+	{
+		name: "areas with a missing parent",
+		query: `
+        SELECT format('%s %s (%s) parent %s', a.type, a.id, a.name, a.parent_id)
+        FROM geographic_area a
+        WHERE a.parent_id IS NOT NULL
+          AND NOT EXISTS (SELECT 1 FROM geographic_area p WHERE p.id = a.parent_id)`,
+	},
+	{
+		name: "admin1 areas without a parent country",
+		query: `
+        SELECT format('admin_1 %s (%s) parent %s', a.id, a.name, COALESCE(p.type::text, 'none'))
+        FROM geographic_area a
+        LEFT JOIN geographic_area p ON p.id = a.parent_id
+        WHERE a.type = 'admin_1' AND (p.id IS NULL OR p.type <> 'country')`,
+	},
+	{
+		name: "areas in a parent cycle",
+		query: `
+        WITH RECURSIVE ancestors (start_id, id, path) AS (
+            SELECT id, parent_id, ARRAY[id]
+            FROM geographic_area
+            WHERE parent_id IS NOT NULL
+            UNION ALL
+            SELECT ancestors.start_id, a.parent_id, ancestors.path || a.id
+            FROM ancestors
+            JOIN geographic_area a ON a.id = ancestors.id
+            WHERE a.parent_id IS NOT NULL AND NOT a.id = ANY(ancestors.path)
+        )
+        SELECT format('%s %s (%s)', a.type, a.id, a.name)
+        FROM geographic_area a
+        WHERE a.id IN (SELECT start_id FROM ancestors WHERE id = start_id)`,
+	},
+}
+
+// IntegrityCheck looks for rows that break the area hierarchy: aggregates referencing areas that
+// don't exist, areas whose parent doesn't exist, admin1 areas not under a country, and areas whose
+// parents loop back to themselves. The foreign keys prevent the first two, but they can still
+// appear if data was loaded with constraints disabled. It returns the checks that found problems
+// with a count and a few sample rows each.
+func (m *Migrator) IntegrityCheck() ([]IntegrityIssue, error) {
+	ctx := context.Background()
+	issues := make([]IntegrityIssue, 0)
+	for _, check := range integrityChecks {
+		issue := IntegrityIssue{Check: check.name}
+		err := m.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM ("+check.query+") offending").Scan(&issue.Count)
+		if err != nil {
+			return nil, fmt.Errorf("integrity check %q failed: %w", check.name, err)
+		}
+		if issue.Count == 0 {
+			continue
+		}
+
+		rows, err := m.db.QueryContext(ctx, fmt.Sprintf("%s LIMIT %d", check.query, integritySampleSize))
+		if err != nil {
+			return nil, fmt.Errorf("integrity check %q failed: %w", check.name, err)
+		}
+		for rows.Next() {
+			var sample string
+			if err := rows.Scan(&sample); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			issue.Samples = append(issue.Samples, sample)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
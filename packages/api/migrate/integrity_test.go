@@ -0,0 +1,36 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIntegrityCheck(t *testing.T) {
+	migrator, db := newTestMigrator(nil)
+	for _, check := range integrityChecks {
+		db.results["SELECT COUNT(*) FROM ("+check.query] = fakeResult{columns: []string{"count"}, rows: [][]any{{0}}}
+	}
+	// An aggregate pointing at a deleted admin1 area
+	dangling := integrityChecks[0].query
+	db.results["SELECT COUNT(*) FROM ("+dangling] = fakeResult{columns: []string{"count"}, rows: [][]any{{1}}}
+	sample := "week 2024-03-02 region 1 country 12 admin1 999 Armed clash"
+	db.results[dangling+" LIMIT"] = fakeResult{columns: []string{"format"}, rows: [][]any{{sample}}}
+
+	issues, err := migrator.IntegrityCheck()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []IntegrityIssue{{Check: "aggregates referencing a missing area", Count: 1, Samples: []string{sample}}}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("IntegrityCheck = %+v, want %+v", issues, want)
+	}
+	if !db.executed(dangling + " LIMIT 5") {
+		t.Error("samples were not limited")
+	}
+
+	// Clean data reports nothing
+	db.results["SELECT COUNT(*) FROM ("+dangling] = fakeResult{columns: []string{"count"}, rows: [][]any{{0}}}
+	if issues, err := migrator.IntegrityCheck(); err != nil || len(issues) != 0 {
+		t.Errorf("IntegrityCheck on clean data = %+v, %v, want no issues", issues, err)
+	}
+}
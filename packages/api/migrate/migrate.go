@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -14,7 +15,8 @@ import (
 	"strings"
 	"time"
 
-	_ "github.com/lib/pq"
+	"crushingviz.info/api/types/acled"
+	"github.com/lib/pq"
 )
 
 // Migration represents a single database migration
@@ -23,20 +25,136 @@ type Migration struct {
 	Description string
 	UpSQL       string
 	DownSQL     string
+	Tags        []string
+	// DeclaredVersion is the version declared via `-- +version 3`, or 0 when the file doesn't declare one
+	DeclaredVersion int
+	// LockTimeout bounds how long the migration waits on locks before failing, set via `-- +locktimeout 5s`
+	LockTimeout time.Duration
+	// MinPGVersion is the lowest Postgres major version the migration runs on, set via `-- +minpgversion 14`
+	MinPGVersion int
+	// Squashes lists the versions a baseline migration replaces, set via `-- +squashes 1,2,3`
+	Squashes []int
+	// AnalyzeTables are analyzed once the migration has committed, set via `-- +analyze table1,table2`
+	AnalyzeTables []string
 }
 
 // Migrator handles database migrations
 type Migrator struct {
-	db         *sql.DB
-	migrations []*Migration
+	db          DB
+	migrations  []*Migration
+	includeTags []string
+	skipTags    []string
+	// skipUnsupported skips migrations requiring a newer Postgres with a warning instead of failing
+	skipUnsupported bool
+	progress        chan<- MigrationProgress
+	// requireDown makes loading fail when any migration has no down SQL
+	requireDown bool
+	clock       acled.Clock
+	// appliedBy is recorded against applied migrations, falling back to the database user
+	appliedBy string
 }
 
 // NewMigrator creates a new migrator instance
 func NewMigrator(db *sql.DB) *Migrator {
+	return NewMigratorWithDB(sqlDB{db: db})
+}
+
+// NewMigratorWithDB creates a new migrator instance backed by any DB implementation
+func NewMigratorWithDB(db DB) *Migrator {
 	return &Migrator{
 		db:         db,
 		migrations: make([]*Migration, 0),
+		clock:      acled.RealClock{},
+	}
+}
+
+// SetTagFilter restricts which migrations are applied going up. When include is non-empty only
+// migrations with at least one of those tags are applied, and migrations with any of the skip
+// tags are never applied.
+func (m *Migrator) SetTagFilter(include, skip []string) {
+	m.includeTags = include
+	m.skipTags = skip
+}
+
+// matchesTags reports whether a migration passes the tag filter
+func (m *Migrator) matchesTags(migration *Migration) bool {
+	hasTag := func(tags []string) bool {
+		for _, tag := range tags {
+			for _, t := range migration.Tags {
+				if t == tag {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	if len(m.includeTags) > 0 && !hasTag(m.includeTags) {
+		return false
 	}
+	return !hasTag(m.skipTags)
+}
+
+// SetSkipUnsupported controls whether migrations whose MinPGVersion isn't met by the server are
+// skipped with a warning (true) or fail the run (false, the default)
+func (m *Migrator) SetSkipUnsupported(skip bool) {
+	m.skipUnsupported = skip
+}
+
+// SetRequireDown controls whether LoadMigrations rejects migrations without down SQL (true) or
+// allows them (false, the default)
+func (m *Migrator) SetRequireDown(require bool) {
+	m.requireDown = require
+}
+
+// SetClock sets the clock used for applied_at timestamps and new migration filenames. Durations of
+// migrations are still measured with the wall clock.
+func (m *Migrator) SetClock(clock acled.Clock) {
+	m.clock = clock
+}
+
+// ServerMajorVersion returns the major version of the connected Postgres server
+func (m *Migrator) ServerMajorVersion() (int, error) {
+	var versionNum string
+	err := m.db.QueryRowContext(context.Background(), "SHOW server_version_num").Scan(&versionNum)
+	if err != nil {
+		return 0, err
+	}
+	num, err := strconv.Atoi(versionNum)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected server_version_num %q", versionNum)
+	}
+	return num / 10000, nil
+}
+
+// parseMigrationFilename extracts the version, description and direction from a migration
+// filename of the form {version}_{description}_up.sql or {version}_{description}_down.sql
+func parseMigrationFilename(fileName string) (version int, description string, isUp bool, err error) {
+	parts := strings.Split(fileName, "_")
+	if len(parts) < 3 {
+		return 0, "", false, fmt.Errorf("invalid migration filename format: %s", fileName)
+	}
+
+	// Parse version number
+	version, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", false, fmt.Errorf("invalid version number in migration: %s", fileName)
+	}
+	if version < 1 {
+		return 0, "", false, fmt.Errorf("migration version must be greater than 0: %s", fileName)
+	}
+
+	// Parse migration type (up or down)
+	lastPart := parts[len(parts)-1]
+	isUp = strings.HasSuffix(lastPart, "up.sql")
+	isDown := strings.HasSuffix(lastPart, "down.sql")
+	if !isUp && !isDown {
+		return 0, "", false, fmt.Errorf("migration file must end with up.sql or down.sql: %s", fileName)
+	}
+
+	// Extract description (everything between version and up/down)
+	description = strings.Join(parts[1:len(parts)-1], "_")
+	return version, description, isUp, nil
 }
 
 // LoadMigrations loads migrations from SQL files in a directory
@@ -49,7 +167,7 @@ func (m *Migrator) LoadMigrations(dirPath string) error {
 	// Clear existing migrations
 	m.migrations = make([]*Migration, 0)
 
-	fmt.Printf("Traversing %s directory\n\n", dirPath)
+	fmt.Fprintf(os.Stderr, "Traversing %s directory\n\n", dirPath)
 
 	err := filepath.Walk(dirPath, func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
@@ -62,32 +180,11 @@ func (m *Migrator) LoadMigrations(dirPath string) error {
 			return nil
 		}
 
-		fileName := filepath.Base(path)
-		parts := strings.Split(fileName, "_")
-		if len(parts) < 3 {
-			return fmt.Errorf("invalid migration filename format: %s", fileName)
-		}
-
-		// Parse version number
-		version, err := strconv.Atoi(parts[0])
+		version, description, isUp, err := parseMigrationFilename(filepath.Base(path))
 		if err != nil {
-			return fmt.Errorf("invalid version number in migration: %s", fileName)
-		}
-		if version < 1 {
-			return fmt.Errorf("migration version must be greater than 0: %s", fileName)
-		}
-
-		// Parse migration type (up or down)
-		lastPart := parts[len(parts)-1]
-		isUp := strings.HasSuffix(lastPart, "up.sql")
-		isDown := strings.HasSuffix(lastPart, "down.sql")
-		if !isUp && !isDown {
-			return fmt.Errorf("migration file must end with up.sql or down.sql: %s", fileName)
+			return err
 		}
 
-		// Extract description (everything between version and up/down)
-		description := strings.Join(parts[1:len(parts)-1], "_")
-
 		// Read file content
 		content, err := os.ReadFile(path)
 		if err != nil {
@@ -126,13 +223,18 @@ func (m *Migrator) LoadMigrations(dirPath string) error {
 			}
 		}
 
-		// Add migration
-		m.migrations = append(m.migrations, &Migration{
+		migration := &Migration{
 			Version:     version,
 			Description: description,
 			UpSQL:       upSQL,
 			DownSQL:     downSQL,
-		})
+		}
+		if err := migration.applyDirectives(parseDirectives(upSQL)); err != nil {
+			return err
+		}
+
+		// Add migration
+		m.migrations = append(m.migrations, migration)
 	}
 
 	// Sort migrations by version
@@ -140,21 +242,50 @@ func (m *Migrator) LoadMigrations(dirPath string) error {
 		return m.migrations[i].Version < m.migrations[j].Version
 	})
 
+	if m.requireDown {
+		var missing []string
+		for _, migration := range m.migrations {
+			if strings.TrimSpace(migration.DownSQL) == "" {
+				missing = append(missing, strconv.Itoa(migration.Version))
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("down migrations are required but missing for versions %s", strings.Join(missing, ", "))
+		}
+	}
+
 	return nil
 }
 
 // Initialize creates the migrations table if it doesn't exist
 func (m *Migrator) Initialize() error {
-	query := `
+	_, err := m.db.ExecContext(context.Background(), initializeSQL)
+	return err
+}
+
+// initializeSQL creates schema_migrations and brings tables created by older versions of the migrator
+// up to date, adding missing columns and widening the version column to fit timestamp versions
+const initializeSQL = `
     CREATE TABLE IF NOT EXISTS schema_migrations (
-        version INT PRIMARY KEY,
+        version BIGINT PRIMARY KEY,
         description TEXT NOT NULL,
         applied_at TIMESTAMP NOT NULL DEFAULT NOW()
-    );`
-
-	_, err := m.db.Exec(query)
-	return err
-}
+    );
+    ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS tags TEXT[];
+    ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS checksum TEXT;
+    ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS applied_by TEXT NOT NULL DEFAULT CURRENT_USER;
+    DO $$
+    BEGIN
+        -- Timestamp versions don't fit the INT column of older tables
+        IF EXISTS (
+            SELECT 1 FROM information_schema.columns
+            WHERE table_schema = current_schema() AND table_name = 'schema_migrations'
+                AND column_name = 'version' AND data_type = 'integer'
+        ) THEN
+            ALTER TABLE schema_migrations ALTER COLUMN version TYPE BIGINT;
+        END IF;
+    END
+    $$;`
 
 // GetCurrentVersion returns the current database schema version
 func (m *Migrator) GetCurrentVersion() (int, error) {
@@ -162,12 +293,15 @@ func (m *Migrator) GetCurrentVersion() (int, error) {
 	query := `
     SELECT COALESCE(MAX(version), 0) FROM schema_migrations;
     `
-	err := m.db.QueryRow(query).Scan(&version)
+	err := m.db.QueryRowContext(context.Background(), query).Scan(&version)
 	return version, err
 }
 
 // UpToVersion migrates the database up to a specific version
 func (m *Migrator) UpToVersion(targetVersion int) error {
+	ctx := context.Background()
+	defer m.closeProgress()
+
 	if len(m.migrations) == 0 {
 		return errors.New("no migrations loaded")
 	}
@@ -182,8 +316,38 @@ func (m *Migrator) UpToVersion(targetVersion int) error {
 		return err
 	}
 
+	// Only already-applied versions have checksums to verify
+	applied, err := m.VerifyChecksums(currentVersion)
+	if err != nil {
+		return err
+	}
+
+	pending := false
+	for _, migration := range m.migrations {
+		if migration.Version > currentVersion && migration.Version <= targetVersion {
+			pending = true
+			break
+		}
+	}
+	if !pending {
+		fmt.Printf("Database up to date (%d applied)\n", applied)
+		return nil
+	}
+
+	// Check the server version once, and only when a pending migration depends on it
+	serverVersion := 0
+	for _, migration := range m.migrations {
+		if migration.Version > currentVersion && migration.Version <= targetVersion && migration.MinPGVersion > 0 {
+			serverVersion, err = m.ServerMajorVersion()
+			if err != nil {
+				return fmt.Errorf("failed to determine server version: %w", err)
+			}
+			break
+		}
+	}
+
 	// Start a transaction
-	tx, err := m.db.Begin()
+	tx, err := m.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -194,6 +358,8 @@ func (m *Migrator) UpToVersion(targetVersion int) error {
 	}()
 
 	// Apply migrations
+	skipped := make([]*Migration, 0)
+	analyzeTables := make([]string, 0)
 	for _, migration := range m.migrations {
 		if migration.Version <= currentVersion {
 			continue // Skip already applied migrations
@@ -203,30 +369,136 @@ func (m *Migrator) UpToVersion(targetVersion int) error {
 			break // Stop at target version
 		}
 
+		if !m.matchesTags(migration) {
+			skipped = append(skipped, migration)
+			fmt.Printf("Skipped migration %d: %s (tags: %s)\n",
+				migration.Version, migration.Description, strings.Join(migration.Tags, ","))
+			continue
+		}
+
+		if migration.MinPGVersion > serverVersion {
+			if !m.skipUnsupported {
+				err = fmt.Errorf("migration %d (%s) requires Postgres %d but the server is version %d",
+					migration.Version, migration.Description, migration.MinPGVersion, serverVersion)
+				return err
+			}
+			skipped = append(skipped, migration)
+			fmt.Printf("Skipped migration %d: %s (requires Postgres %d, server is %d)\n",
+				migration.Version, migration.Description, migration.MinPGVersion, serverVersion)
+			continue
+		}
+
+		// Migrations are tracked by highest version, so applying a later migration
+		// leaves any skipped ones behind it as a hole that future runs won't fill
+		for _, s := range skipped {
+			fmt.Printf("Warning: migration %d (%s) was skipped and will not be applied once migration %d is recorded\n",
+				s.Version, s.Description, migration.Version)
+		}
+		skipped = skipped[:0]
+
+		start := time.Now()
+		m.emit(MigrationStarted, migration, start, nil)
+
+		// Fail fast rather than queuing behind (and blocking) application traffic
+		if migration.LockTimeout > 0 {
+			_, err = tx.ExecContext(ctx, lockTimeoutSQL(migration.LockTimeout))
+			if err != nil {
+				m.emit(MigrationFailed, migration, start, err)
+				return fmt.Errorf("failed to set lock timeout for migration %d: %w", migration.Version, err)
+			}
+		}
+
 		// Execute migration
-		_, err = tx.Exec(migration.UpSQL)
+		_, err = tx.ExecContext(ctx, migration.UpSQL)
 		if err != nil {
+			m.emit(MigrationFailed, migration, start, err)
 			return fmt.Errorf("failed to apply migration %d (%s): %w",
 				migration.Version, migration.Description, err)
 		}
+		if migration.LockTimeout > 0 {
+			_, err = tx.ExecContext(ctx, resetLockTimeoutSQL)
+			if err != nil {
+				m.emit(MigrationFailed, migration, start, err)
+				return fmt.Errorf("failed to reset lock timeout after migration %d: %w", migration.Version, err)
+			}
+		}
 
 		// Record migration
-		_, err = tx.Exec(`
-            INSERT INTO schema_migrations (version, description, applied_at)
-            VALUES ($1, $2, $3)
-        `, migration.Version, migration.Description, time.Now())
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO schema_migrations (version, description, applied_at, tags, checksum, applied_by)
+            VALUES ($1, $2, $3, $4, $5, COALESCE(NULLIF($6, ''), CURRENT_USER))
+        `, migration.Version, migration.Description, m.clock.Now(), pq.Array(migration.Tags), migration.Checksum(), m.appliedBy)
 		if err != nil {
+			m.emit(MigrationFailed, migration, start, err)
 			return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
 		}
 
+		m.emit(MigrationApplied, migration, start, nil)
 		fmt.Printf("Applied migration %d: %s\n", migration.Version, migration.Description)
+		analyzeTables = appendUnique(analyzeTables, migration.AnalyzeTables...)
 	}
 
-	return tx.Commit()
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	// Refresh planner statistics now rather than waiting for autovacuum. ANALYZE runs outside the
+	// migration transaction, so a failure here leaves the migrations applied.
+	for _, table := range analyzeTables {
+		if _, err = m.db.ExecContext(ctx, analyzeSQL(table)); err != nil {
+			return fmt.Errorf("migrations were applied but analyzing %s failed: %w", table, err)
+		}
+		fmt.Printf("Analyzed %s\n", table)
+	}
+	return nil
 }
 
+// analyzeSQL returns the ANALYZE statement for a table name validated by the analyze directive
+func analyzeSQL(table string) string {
+	return "ANALYZE " + quoteQualifiedName(table)
+}
+
+// quoteQualifiedName quotes each part of a possibly schema-qualified name, e.g. public.acled_event
+// becomes "public"."acled_event"
+func quoteQualifiedName(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = pq.QuoteIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// appendUnique appends the values not already in list
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// lockTimeoutSQL returns the statement limiting lock waits for the rest of the current transaction
+func lockTimeoutSQL(timeout time.Duration) string {
+	return fmt.Sprintf("SET LOCAL lock_timeout = '%dms'", timeout.Milliseconds())
+}
+
+// resetLockTimeoutSQL restores the session's lock_timeout after a migration that set its own, so the
+// timeout doesn't carry over to the migrations after it in the same transaction
+const resetLockTimeoutSQL = "SET LOCAL lock_timeout = DEFAULT"
+
 // UpAll migrates the database to the latest version
 func (m *Migrator) UpAll() error {
+	// UpToVersion closes the progress channel itself, this covers returning before calling it
+	defer m.closeProgress()
+
 	if len(m.migrations) == 0 {
 		return errors.New("no migrations loaded")
 	}
@@ -238,6 +510,9 @@ func (m *Migrator) UpAll() error {
 
 // DownToVersion migrates the database down to a specific version
 func (m *Migrator) DownToVersion(targetVersion int) error {
+	ctx := context.Background()
+	defer m.closeProgress()
+
 	if len(m.migrations) == 0 {
 		return errors.New("no migrations loaded")
 	}
@@ -257,7 +532,7 @@ func (m *Migrator) DownToVersion(targetVersion int) error {
 	}
 
 	// Get applied migrations
-	rows, err := m.db.Query(`
+	rows, err := m.db.QueryContext(ctx, `
         SELECT version, description
         FROM schema_migrations
         WHERE version > $1
@@ -293,7 +568,7 @@ func (m *Migrator) DownToVersion(targetVersion int) error {
 	}
 
 	// Start a transaction
-	tx, err := m.db.Begin()
+	tx, err := m.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -314,22 +589,28 @@ func (m *Migrator) DownToVersion(targetVersion int) error {
 			return fmt.Errorf("down migration SQL is empty for version %d", am.Version)
 		}
 
+		start := time.Now()
+		m.emit(MigrationStarted, migration, start, nil)
+
 		// Execute down migration
-		_, err = tx.Exec(migration.DownSQL)
+		_, err = tx.ExecContext(ctx, migration.DownSQL)
 		if err != nil {
+			m.emit(MigrationFailed, migration, start, err)
 			return fmt.Errorf("failed to apply down migration %d (%s): %w",
 				migration.Version, migration.Description, err)
 		}
 
 		// Remove migration record
-		_, err = tx.Exec(`
+		_, err = tx.ExecContext(ctx, `
             DELETE FROM schema_migrations
             WHERE version = $1
         `, migration.Version)
 		if err != nil {
+			m.emit(MigrationFailed, migration, start, err)
 			return fmt.Errorf("failed to remove migration record %d: %w", migration.Version, err)
 		}
 
+		m.emit(MigrationReverted, migration, start, nil)
 		fmt.Printf("Reverted migration %d: %s\n", migration.Version, migration.Description)
 	}
 
@@ -338,6 +619,9 @@ func (m *Migrator) DownToVersion(targetVersion int) error {
 
 // Down reverts the most recent migration
 func (m *Migrator) Down() error {
+	// DownToVersion closes the progress channel itself, this covers returning before calling it
+	defer m.closeProgress()
+
 	currentVersion, err := m.GetCurrentVersion()
 	if err != nil {
 		return err
@@ -350,19 +634,52 @@ func (m *Migrator) Down() error {
 	return m.DownToVersion(currentVersion - 1)
 }
 
+// extractFlag removes a boolean flag from os.Args and reports whether it was present
+func extractFlag(name string) bool {
+	for i := 1; i < len(os.Args); i++ {
+		if os.Args[i] == name {
+			os.Args = append(os.Args[:i], os.Args[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// extractOption removes a `name value` pair from os.Args and returns the value
+func extractOption(name string) (string, bool) {
+	for i := 1; i < len(os.Args)-1; i++ {
+		if os.Args[i] == name {
+			value := os.Args[i+1]
+			os.Args = append(os.Args[:i], os.Args[i+2:]...)
+			return value, true
+		}
+	}
+	return "", false
+}
+
 func main() {
 	// Connect to the database
 	connStr := os.Getenv("POSTGRES_CONNECTION_STRING")
 	if len(connStr) == 0 {
 		fmt.Printf("Connection string not found\n")
 	}
-	fmt.Printf("Connecting to DB using connection string %s\n", connStr)
+	connStr, err := BuildConnStr(connStr, TLSConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Invalid TLS configuration: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "Connecting to DB using connection string %s\n", connStr)
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 	defer db.Close()
 
+	pool, err := PoolConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid pool configuration: %v", err)
+	}
+	pool.Apply(db)
+
 	// Create a migrator
 	migrator := NewMigrator(db)
 
@@ -378,6 +695,35 @@ func main() {
 		os.Args = append(os.Args[:1], os.Args[3:]...)
 	}
 
+	tags, ok := extractOption("--tags")
+	skipTags, skipOk := extractOption("--skip-tags")
+	if ok || skipOk {
+		migrator.SetTagFilter(splitList(tags), splitList(skipTags))
+	}
+	migrator.SetSkipUnsupported(extractFlag("--skip-unsupported"))
+	migrator.SetRequireDown(extractFlag("--require-down"))
+	migrator.SetAppliedBy(os.Getenv("MIGRATION_APPLIED_BY"))
+	if extractFlag("--verify-only") {
+		migrator.SetVerifyOnly()
+	}
+	where, _ := extractOption("--where")
+	limitOption, _ := extractOption("--limit")
+	format, _ := extractOption("--format")
+	runsOption, _ := extractOption("--runs")
+	batchOption, _ := extractOption("--batch-size")
+	fromOption, hasFrom := extractOption("--from")
+	scheme := NamingScheme(os.Getenv("MIGRATION_NAMING_SCHEME"))
+	if option, ok := extractOption("--scheme"); ok {
+		scheme = NamingScheme(option)
+	}
+	if scheme == "" {
+		scheme = NamingSchemeInteger
+	}
+	outDir, ok := extractOption("--out")
+	if !ok {
+		outDir = migrationsDir
+	}
+
 	err = migrator.LoadMigrations(migrationsDir)
 	if err != nil {
 		log.Fatalf("Failed to load migrations: %v", err)
@@ -385,7 +731,7 @@ func main() {
 
 	// Print usage if no arguments provided
 	if len(os.Args) < 2 {
-		fmt.Println("Usage: migrate [dir MIGRATIONS_DIR] [up|down|up-to VERSION|down-to VERSION|status]")
+		fmt.Println("Usage: migrate [dir MIGRATIONS_DIR] [--tags TAGS] [--skip-tags TAGS] [--skip-unsupported] [--require-down] [--verify-only] [up|down|verify|up-to VERSION|down-to VERSION|restore-to VERSION|status|check|integrity-check|backfill-disorder [--batch-size N]|plan VERSION...|history [--format text|csv|json]|compact BASELINE|shadow|bench [--runs N] VERSION|create [--scheme integer|timestamp] [--out DIR] DESCRIPTION|bundle [--from VERSION]|bundle-down [--from VERSION] VERSION|initscript|areas-export|areas-import FILE|datadump [--where CONDITION] [--limit N] TABLE...]")
 		os.Exit(1)
 	}
 
@@ -418,12 +764,235 @@ func main() {
 			os.Exit(1)
 		}
 		err = migrator.DownToVersion(version)
+	case "restore-to":
+		if len(os.Args) < 3 {
+			fmt.Println("Missing version number")
+			os.Exit(1)
+		}
+		var version int
+		version, err = strconv.Atoi(os.Args[2])
+		if err != nil {
+			fmt.Printf("Invalid version number: %s\n", os.Args[2])
+			os.Exit(1)
+		}
+		err = migrator.RestoreTo(version)
 	case "status":
 		currentVersion, err := migrator.GetCurrentVersion()
 		if err != nil {
 			log.Fatalf("Failed to get current version: %v", err)
 		}
 		fmt.Printf("Current database version: %d\n", currentVersion)
+	case "verify":
+		migrator.SetVerifyOnly()
+		var result VerifyResult
+		result, err = migrator.Verify()
+		if err == nil {
+			fmt.Printf("Database version %d, %d applied migrations verified\n", result.CurrentVersion, result.Checked)
+			for _, migration := range result.Pending {
+				fmt.Printf("Pending: %d %s\n", migration.Version, migration.Description)
+			}
+		}
+	case "plan":
+		if len(os.Args) < 3 {
+			fmt.Println("Missing version number")
+			os.Exit(1)
+		}
+		targets := make([]int, 0, len(os.Args)-2)
+		for _, arg := range os.Args[2:] {
+			target, convErr := strconv.Atoi(arg)
+			if convErr != nil {
+				fmt.Printf("Invalid version number: %s\n", arg)
+				os.Exit(1)
+			}
+			targets = append(targets, target)
+		}
+		var currentVersion int
+		currentVersion, err = migrator.GetCurrentVersion()
+		if err != nil {
+			log.Fatalf("Failed to get current version: %v", err)
+		}
+		var plan []*Migration
+		plan, err = migrator.Plan(targets, currentVersion)
+		if err == nil && len(plan) == 0 {
+			fmt.Printf("Nothing to apply, database is at version %d\n", currentVersion)
+		}
+		for _, migration := range plan {
+			fmt.Printf("%d %s\n", migration.Version, migration.Description)
+		}
+	case "integrity-check":
+		var issues []IntegrityIssue
+		issues, err = migrator.IntegrityCheck()
+		for _, issue := range issues {
+			fmt.Printf("%s: %d\n", issue.Check, issue.Count)
+			for _, sample := range issue.Samples {
+				fmt.Printf("  %s\n", sample)
+			}
+		}
+		if err == nil && len(issues) > 0 {
+			err = fmt.Errorf("%d integrity checks failed", len(issues))
+		}
+	case "backfill-disorder":
+		batchSize := defaultBackfillBatchSize
+		if batchOption != "" {
+			batchSize, err = strconv.Atoi(batchOption)
+			if err != nil || batchSize < 1 {
+				fmt.Printf("Invalid batch size: %s\n", batchOption)
+				os.Exit(1)
+			}
+		}
+		var corrected int
+		corrected, err = migrator.BackfillDisorder(batchSize)
+		fmt.Printf("Corrected the disorder type of %d rows\n", corrected)
+	case "check":
+		var mismatches []string
+		mismatches, err = migrator.CheckRecorded()
+		for _, mismatch := range mismatches {
+			fmt.Println(mismatch)
+		}
+		if err == nil && len(mismatches) > 0 {
+			err = fmt.Errorf("%d migrations don't match their files or recorded state", len(mismatches))
+		}
+	case "history":
+		var entries []HistoryEntry
+		entries, err = migrator.History()
+		if err == nil {
+			err = WriteHistory(os.Stdout, entries, format)
+		}
+	case "create":
+		if len(os.Args) < 3 {
+			fmt.Println("Missing migration description")
+			os.Exit(1)
+		}
+		var paths []string
+		paths, err = migrator.CreateMigration(outDir, strings.Join(os.Args[2:], " "), scheme, migrator.clock.Now())
+		for _, path := range paths {
+			fmt.Printf("Created %s\n", path)
+		}
+	case "compact":
+		if len(os.Args) < 3 {
+			fmt.Println("Missing baseline version")
+			os.Exit(1)
+		}
+		var baseline int
+		baseline, err = strconv.Atoi(os.Args[2])
+		if err != nil {
+			fmt.Printf("Invalid version number: %s\n", os.Args[2])
+			os.Exit(1)
+		}
+		err = migrator.Compact(baseline)
+	case "shadow":
+		var result ShadowResult
+		result, err = migrator.Shadow()
+		if err == nil {
+			fmt.Printf("Rehearsed %d pending migrations in %s\n", len(result.Durations), result.Total)
+		}
+	case "bench":
+		if len(os.Args) < 3 {
+			fmt.Println("Missing version number")
+			os.Exit(1)
+		}
+		var version int
+		version, err = strconv.Atoi(os.Args[2])
+		if err != nil {
+			fmt.Printf("Invalid version number: %s\n", os.Args[2])
+			os.Exit(1)
+		}
+		runs := 1
+		if runsOption != "" {
+			runs, err = strconv.Atoi(runsOption)
+			if err != nil || runs < 1 {
+				fmt.Printf("Invalid runs: %s\n", runsOption)
+				os.Exit(1)
+			}
+		}
+		var results []BenchRun
+		results, err = migrator.Bench(version, runs)
+		for i, run := range results {
+			fmt.Printf("Run %d: %s, %d rows affected\n", i+1, run.Duration, run.RowsAffected)
+		}
+	case "bundle", "bundle-down":
+		fromVersion := 0
+		if hasFrom {
+			fromVersion, err = strconv.Atoi(fromOption)
+			if err != nil {
+				fmt.Printf("Invalid version number: %s\n", fromOption)
+				os.Exit(1)
+			}
+		} else if fromVersion, err = migrator.GetCurrentVersion(); err != nil {
+			log.Fatalf("Failed to get current version (use --from for a database without schema_migrations): %v", err)
+		}
+		if command == "bundle" {
+			toVersion := fromVersion
+			for _, migration := range migrator.migrations {
+				toVersion = max(toVersion, migration.Version)
+			}
+			err = migrator.WriteUpBundle(os.Stdout, fromVersion, toVersion)
+		} else {
+			if len(os.Args) < 3 {
+				fmt.Println("Missing version number")
+				os.Exit(1)
+			}
+			var toVersion int
+			toVersion, err = strconv.Atoi(os.Args[2])
+			if err != nil {
+				fmt.Printf("Invalid version number: %s\n", os.Args[2])
+				os.Exit(1)
+			}
+			err = migrator.WriteDownBundle(os.Stdout, fromVersion, toVersion)
+		}
+		if err != nil {
+			log.Fatalf("Bundle failed: %v", err)
+		}
+		// Keep stdout limited to the bundled SQL
+		return
+	case "initscript":
+		if err = migrator.WriteInitScript(os.Stdout); err != nil {
+			log.Fatalf("Init script failed: %v", err)
+		}
+		// Keep stdout limited to the script
+		return
+	case "areas-export":
+		if err = migrator.ExportAreas(os.Stdout); err != nil {
+			log.Fatalf("Area export failed: %v", err)
+		}
+		// Keep stdout limited to the exported GeoJSON
+		return
+	case "areas-import":
+		if len(os.Args) < 3 {
+			fmt.Println("Missing GeoJSON file")
+			os.Exit(1)
+		}
+		var file *os.File
+		file, err = os.Open(os.Args[2])
+		if err != nil {
+			log.Fatalf("Failed to open %s: %v", os.Args[2], err)
+		}
+		defer file.Close()
+		var imported int
+		imported, err = migrator.ImportAreas(file)
+		if err == nil {
+			fmt.Printf("Imported %d areas\n", imported)
+		}
+	case "datadump":
+		if len(os.Args) < 3 {
+			fmt.Println("Missing table name")
+			os.Exit(1)
+		}
+		limit := 0
+		if limitOption != "" {
+			limit, err = strconv.Atoi(limitOption)
+			if err != nil || limit < 0 {
+				fmt.Printf("Invalid limit: %s\n", limitOption)
+				os.Exit(1)
+			}
+		}
+		for _, table := range os.Args[2:] {
+			if err = migrator.DataDump(os.Stdout, table, where, limit); err != nil {
+				log.Fatalf("Data dump failed: %v", err)
+			}
+		}
+		// Keep stdout limited to the dumped SQL
+		return
 	default:
 		fmt.Println("Unknown command")
 		os.Exit(1)
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeMigrationFiles writes migration files, keyed by name, into a new directory and returns it
+func writeMigrationFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// taggedMigrations loads a schema migration, a seed migration and a migration tagged with both
+func taggedMigrations(t *testing.T) []*Migration {
+	t.Helper()
+	dir := writeMigrationFiles(t, map[string]string{
+		"001_schema_up.sql":   "-- +tags schema\nCREATE TABLE places (id INT);",
+		"001_schema_down.sql": "DROP TABLE places;",
+		"002_seed_up.sql":     "-- +tags seed\nINSERT INTO places VALUES (1);",
+		"002_seed_down.sql":   "DELETE FROM places;",
+		"003_both_up.sql":     "-- +tags schema, seed\nALTER TABLE places ADD name TEXT;",
+		"003_both_down.sql":   "ALTER TABLE places DROP name;",
+	})
+	migrator := NewMigratorWithDB(newFakeDB())
+	if err := migrator.LoadMigrations(dir); err != nil {
+		t.Fatal(err)
+	}
+	return migrator.migrations
+}
+
+func TestLoadMigrationsParsesTags(t *testing.T) {
+	migrations := taggedMigrations(t)
+	want := [][]string{{"schema"}, {"seed"}, {"schema", "seed"}}
+	for i, migration := range migrations {
+		if !reflect.DeepEqual(migration.Tags, want[i]) {
+			t.Errorf("migration %d tags = %q, want %q", migration.Version, migration.Tags, want[i])
+		}
+	}
+}
+
+func TestTagFilter(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		include, skip []string
+		want          []int
+	}{
+		{"no filter", nil, nil, []int{1, 2, 3}},
+		{"include schema", []string{"schema"}, nil, []int{1, 3}},
+		{"skip seed", nil, []string{"seed"}, []int{1}},
+		{"include schema skip seed", []string{"schema"}, []string{"seed"}, []int{1}},
+		{"include unknown", []string{"reference"}, nil, []int{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			migrator, db := newTestMigrator(taggedMigrations(t))
+			migrator.SetTagFilter(tt.include, tt.skip)
+			if err := migrator.UpAll(); err != nil {
+				t.Fatalf("UpAll: %v", err)
+			}
+			if got := db.versions(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applied versions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppliedTagsAreRecorded(t *testing.T) {
+	migrator, db := newTestMigrator(taggedMigrations(t))
+	if err := migrator.UpAll(); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.applied[3].tags; !reflect.DeepEqual(got, []string{"schema", "seed"}) {
+		t.Errorf("recorded tags = %q, want [schema seed]", got)
+	}
+}
+
+func TestLockTimeoutIsScopedToItsMigration(t *testing.T) {
+	dir := writeMigrationFiles(t, map[string]string{
+		"001_add_index_up.sql":   "-- +locktimeout 5s\nCREATE INDEX places_name ON places (name);",
+		"001_add_index_down.sql": "DROP INDEX places_name;",
+		"002_backfill_up.sql":    "UPDATE places SET name = '';",
+		"002_backfill_down.sql":  "",
+	})
+	migrator, db := newTestMigrator(nil)
+	if err := migrator.LoadMigrations(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := migrator.migrations[0].LockTimeout; got != 5*time.Second {
+		t.Fatalf("LockTimeout = %s, want 5s", got)
+	}
+
+	if err := migrator.UpAll(); err != nil {
+		t.Fatal(err)
+	}
+	set := db.indexOf("SET LOCAL lock_timeout = '5000ms'")
+	index := db.indexOf("CREATE INDEX places_name")
+	reset := db.indexOf(resetLockTimeoutSQL)
+	backfill := db.indexOf("UPDATE places")
+	if set < 0 || !(set < index && index < reset && reset < backfill) {
+		t.Errorf("lock timeout statements out of order: set %d, migration %d, reset %d, next migration %d",
+			set, index, reset, backfill)
+	}
+
+	var bundle strings.Builder
+	if err := migrator.WriteUpBundle(&bundle, 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	script := bundle.String()
+	if !(strings.Index(script, "CREATE INDEX places_name") < strings.Index(script, resetLockTimeoutSQL) &&
+		strings.Index(script, resetLockTimeoutSQL) < strings.Index(script, "UPDATE places")) {
+		t.Errorf("bundle does not reset the lock timeout between migrations:\n%s", script)
+	}
+}
+
+func TestMinPGVersion(t *testing.T) {
+	dir := writeMigrationFiles(t, map[string]string{
+		"001_base_up.sql":        "CREATE TABLE places (id INT);",
+		"001_base_down.sql":      "DROP TABLE places;",
+		"002_merge_up.sql":       "-- +minpgversion 15\nMERGE INTO places USING staged ON true WHEN MATCHED THEN DO NOTHING;",
+		"002_merge_down.sql":     "",
+		"003_add_name_up.sql":    "ALTER TABLE places ADD name TEXT;",
+		"003_add_name_down.sql":  "ALTER TABLE places DROP name;",
+		"004_uses_pg14_up.sql":   "-- +minpgversion 14\nSELECT 1;",
+		"004_uses_pg14_down.sql": "",
+	})
+
+	migrator, db := newTestMigrator(nil)
+	if err := migrator.LoadMigrations(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := migrator.migrations[1].MinPGVersion; got != 15 {
+		t.Fatalf("MinPGVersion = %d, want 15", got)
+	}
+	migrations := migrator.migrations
+
+	// Postgres 14 can't apply migration 2, so the whole run fails
+	migrator, db = newTestMigrator(migrations)
+	db.serverVersionNum = "140011"
+	if err := migrator.UpAll(); err == nil || !strings.Contains(err.Error(), "requires Postgres 15") {
+		t.Errorf("UpAll on Postgres 14 = %v, want a version error", err)
+	}
+	if len(db.versions()) != 0 {
+		t.Errorf("failed run applied %v", db.versions())
+	}
+
+	// With --skip-unsupported it is skipped and the rest applied
+	migrator, db = newTestMigrator(migrations)
+	db.serverVersionNum = "140011"
+	migrator.SetSkipUnsupported(true)
+	if err := migrator.UpAll(); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.versions(); !reflect.DeepEqual(got, []int{1, 3, 4}) {
+		t.Errorf("applied versions = %v, want [1 3 4]", got)
+	}
+
+	// Postgres 16 runs everything
+	migrator, db = newTestMigrator(migrations)
+	if err := migrator.UpAll(); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.versions(); !reflect.DeepEqual(got, []int{1, 2, 3, 4}) {
+		t.Errorf("applied versions = %v, want [1 2 3 4]", got)
+	}
+}
+
+func TestRequireDown(t *testing.T) {
+	dir := writeMigrationFiles(t, map[string]string{
+		"001_reversible_up.sql":   "CREATE TABLE places (id INT);",
+		"001_reversible_down.sql": "DROP TABLE places;",
+		"002_no_down_up.sql":      "INSERT INTO places VALUES (1);",
+		"003_blank_down_up.sql":   "ALTER TABLE places ADD name TEXT;",
+		"003_blank_down_down.sql": "  \n",
+	})
+
+	migrator := NewMigratorWithDB(newFakeDB())
+	if err := migrator.LoadMigrations(dir); err != nil {
+		t.Fatalf("down SQL should be optional by default: %v", err)
+	}
+
+	migrator = NewMigratorWithDB(newFakeDB())
+	migrator.SetRequireDown(true)
+	err := migrator.LoadMigrations(dir)
+	if err == nil || !strings.Contains(err.Error(), "versions 2, 3") {
+		t.Errorf("LoadMigrations with --require-down = %v, want versions 2 and 3 named", err)
+	}
+}
+
+func TestAnalyzeRunsAfterCommit(t *testing.T) {
+	dir := writeMigrationFiles(t, map[string]string{
+		"001_load_up.sql": "-- +analyze acled_weekly_agg, public.geographic_area\nINSERT INTO acled_weekly_agg SELECT 1;",
+		"002_more_up.sql": "-- +analyze acled_weekly_agg\nINSERT INTO acled_weekly_agg SELECT 2;",
+	})
+	migrator, db := newTestMigrator(nil)
+	if err := migrator.LoadMigrations(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.UpAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	commit := db.indexOf("COMMIT")
+	weekly := db.indexOf(`ANALYZE "acled_weekly_agg"`)
+	areas := db.indexOf(`ANALYZE "public"."geographic_area"`)
+	if commit < 0 || weekly < commit || areas < commit {
+		t.Errorf("ANALYZE didn't run after the commit: %q", db.statements)
+	}
+	count := 0
+	for _, statement := range db.statements {
+		if strings.HasPrefix(statement, "ANALYZE") {
+			count++
+		}
+	}
+	if count != 2 {
+		t.Errorf("ran %d ANALYZE statements, want each table once", count)
+	}
+
+	bad := writeMigrationFiles(t, map[string]string{
+		"001_load_up.sql": "-- +analyze acled_weekly_agg; DROP TABLE users\nSELECT 1;",
+	})
+	if err := NewMigratorWithDB(newFakeDB()).LoadMigrations(bad); err == nil || !strings.Contains(err.Error(), "invalid table name") {
+		t.Errorf("LoadMigrations with an invalid table = %v, want an invalid table name error", err)
+	}
+}
@@ -0,0 +1,15 @@
+-- +tags schema
+
+-- Create ingestion_log table recording what each ingestion run changed
+CREATE TABLE ingestion_log (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    source TEXT NOT NULL,
+    type TEXT NOT NULL,
+    weeks TIMESTAMP[] NOT NULL,
+    inserted_count INTEGER NOT NULL,
+    updated_count INTEGER NOT NULL,
+    fatalities_delta INTEGER NOT NULL
+);
+
+CREATE INDEX idx_ingestion_log_source_created ON ingestion_log(source, created_at);
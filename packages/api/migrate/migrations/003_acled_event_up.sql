@@ -0,0 +1,22 @@
+-- +tags schema
+
+-- Create acled_event table holding raw, non-aggregated ACLED events
+CREATE TABLE acled_event (
+    event_id TEXT PRIMARY KEY,
+    event_date DATE NOT NULL,
+    region_id INTEGER NOT NULL REFERENCES geographic_area(id),
+    country_id INTEGER REFERENCES geographic_area(id),
+    admin1_id INTEGER REFERENCES geographic_area(id),
+    location TEXT,
+    actor1 TEXT,
+    actor2 TEXT,
+    disorder_type disorder_type,
+    event_type event_type,
+    sub_event_type sub_event_type NOT NULL,
+    fatalities INTEGER NOT NULL DEFAULT 0,
+    longitude DECIMAL,
+    latitude DECIMAL
+);
+
+CREATE INDEX idx_acled_event_country_date ON acled_event(country_id, event_date);
+CREATE INDEX idx_acled_event_admin1_date ON acled_event(admin1_id, event_date);
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Plan returns, in order, every migration that has to run to reach all of the target versions from
+// currentVersion. Since migrations are applied in version order, reaching a target means applying
+// every pending migration below it too. Migrations excluded by the tag filter are left out, as
+// UpToVersion would skip them. It fails if any target version has no migration file, and returns
+// an empty plan when all targets are already applied.
+func (m *Migrator) Plan(targets []int, currentVersion int) ([]*Migration, error) {
+	loaded := make(map[int]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		loaded[migration.Version] = true
+	}
+
+	var missing []string
+	highest := currentVersion
+	for _, target := range targets {
+		if !loaded[target] {
+			missing = append(missing, strconv.Itoa(target))
+			continue
+		}
+		highest = max(highest, target)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no migration found for versions %s", strings.Join(missing, ", "))
+	}
+
+	plan := make([]*Migration, 0)
+	for _, migration := range m.migrations {
+		if migration.Version > currentVersion && migration.Version <= highest && m.matchesTags(migration) {
+			plan = append(plan, migration)
+		}
+	}
+	return plan, nil
+}
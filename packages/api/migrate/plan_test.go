@@ -0,0 +1,56 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// planVersions returns the versions of a plan
+func planVersions(plan []*Migration) []int {
+	versions := make([]int, len(plan))
+	for i, migration := range plan {
+		versions[i] = migration.Version
+	}
+	return versions
+}
+
+func TestPlan(t *testing.T) {
+	migrations := append(testMigrations(), &Migration{Version: 5, Description: "seed", Tags: []string{"seed"}})
+	migrator, _ := newTestMigrator(migrations)
+
+	for _, tt := range []struct {
+		name           string
+		targets        []int
+		currentVersion int
+		want           []int
+	}{
+		{"includes lower pending versions", []int{3}, 0, []int{1, 2, 3}},
+		{"highest target wins", []int{2, 3}, 1, []int{2, 3}},
+		{"already applied", []int{1, 2}, 3, []int{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := migrator.Plan(tt.targets, tt.currentVersion)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := planVersions(plan); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("plan = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	migrator.SetTagFilter(nil, []string{"seed"})
+	plan, err := migrator.Plan([]int{5}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := planVersions(plan); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("plan with seed skipped = %v, want [3]", got)
+	}
+
+	_, err = migrator.Plan([]int{3, 4, 9}, 0)
+	if err == nil || !strings.Contains(err.Error(), "versions 4, 9") {
+		t.Errorf("Plan with missing versions = %v, want 4 and 9 named", err)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// PoolConfig holds the connection pool settings applied to the *sql.DB
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// DefaultPoolConfig bounds the pool so a busy database isn't exhausted, while keeping a few idle
+// connections to avoid reconnect churn. The migrator applies everything in one transaction on a
+// single connection, so it never needs more than a couple.
+var DefaultPoolConfig = PoolConfig{
+	MaxOpenConns:    10,
+	MaxIdleConns:    2,
+	ConnMaxLifetime: 30 * time.Minute,
+}
+
+// PoolConfigFromEnv reads pool settings from POSTGRES_MAX_OPEN_CONNS, POSTGRES_MAX_IDLE_CONNS and
+// POSTGRES_CONN_MAX_LIFETIME (a Go duration, e.g. 10m), using DefaultPoolConfig for unset values
+func PoolConfigFromEnv() (PoolConfig, error) {
+	c := DefaultPoolConfig
+	for _, setting := range []struct {
+		name  string
+		value *int
+	}{
+		{"POSTGRES_MAX_OPEN_CONNS", &c.MaxOpenConns},
+		{"POSTGRES_MAX_IDLE_CONNS", &c.MaxIdleConns},
+	} {
+		raw := os.Getenv(setting.name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			return c, fmt.Errorf("invalid %s %q", setting.name, raw)
+		}
+		*setting.value = value
+	}
+	if raw := os.Getenv("POSTGRES_CONN_MAX_LIFETIME"); raw != "" {
+		lifetime, err := time.ParseDuration(raw)
+		if err != nil || lifetime < 0 {
+			return c, fmt.Errorf("invalid POSTGRES_CONN_MAX_LIFETIME %q", raw)
+		}
+		c.ConnMaxLifetime = lifetime
+	}
+	return c, nil
+}
+
+// Apply sets the pool settings on db. As in database/sql, 0 means unlimited for MaxOpenConns and
+// ConnMaxLifetime, and 0 idle connections disables idle reuse.
+func (c PoolConfig) Apply(db *sql.DB) {
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	db.SetMaxIdleConns(c.MaxIdleConns)
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+}
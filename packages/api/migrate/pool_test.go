@@ -0,0 +1,58 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestPoolConfigFromEnv(t *testing.T) {
+	t.Setenv("POSTGRES_MAX_OPEN_CONNS", "")
+	t.Setenv("POSTGRES_MAX_IDLE_CONNS", "")
+	t.Setenv("POSTGRES_CONN_MAX_LIFETIME", "")
+	config, err := PoolConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config != DefaultPoolConfig {
+		t.Errorf("config with nothing set = %+v, want the defaults", config)
+	}
+
+	t.Setenv("POSTGRES_MAX_OPEN_CONNS", "1")
+	t.Setenv("POSTGRES_MAX_IDLE_CONNS", "0")
+	t.Setenv("POSTGRES_CONN_MAX_LIFETIME", "5m")
+	config, err = PoolConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (PoolConfig{MaxOpenConns: 1, MaxIdleConns: 0, ConnMaxLifetime: 5 * time.Minute}); config != want {
+		t.Errorf("config = %+v, want %+v", config, want)
+	}
+
+	for name, value := range map[string]string{
+		"POSTGRES_MAX_OPEN_CONNS":    "-1",
+		"POSTGRES_MAX_IDLE_CONNS":    "two",
+		"POSTGRES_CONN_MAX_LIFETIME": "30",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if _, err := PoolConfigFromEnv(); err == nil {
+				t.Errorf("expected an error for %s=%q", name, value)
+			}
+		})
+	}
+}
+
+func TestPoolConfigApply(t *testing.T) {
+	// sql.Open doesn't connect, so the pool settings can be checked without a database
+	db, err := sql.Open("postgres", "postgres://localhost/none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	PoolConfig{MaxOpenConns: 3, MaxIdleConns: 1, ConnMaxLifetime: time.Minute}.Apply(db)
+	if got := db.Stats().MaxOpenConnections; got != 3 {
+		t.Errorf("MaxOpenConnections = %d, want 3", got)
+	}
+}
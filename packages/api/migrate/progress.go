@@ -0,0 +1,71 @@
+package main
+
+import "time"
+
+// progressBufferSize is the buffer of channels made by NewProgressChannel, enough to hold
+// every event of a typical run without a consumer keeping up
+const progressBufferSize = 64
+
+// MigrationEvent is the kind of progress being reported for a migration
+type MigrationEvent string
+
+const (
+	MigrationStarted  MigrationEvent = "started"
+	MigrationApplied  MigrationEvent = "applied"
+	MigrationReverted MigrationEvent = "reverted"
+	MigrationFailed   MigrationEvent = "failed"
+)
+
+// MigrationProgress is sent on the progress channel as migrations run
+type MigrationProgress struct {
+	Event       MigrationEvent
+	Version     int
+	Description string
+	// Duration is how long the migration took, set for applied, reverted and failed events
+	Duration time.Duration
+	// Err is set for failed events
+	Err error
+}
+
+// NewProgressChannel makes a buffered channel suitable for SetProgress
+func NewProgressChannel() chan MigrationProgress {
+	return make(chan MigrationProgress, progressBufferSize)
+}
+
+// SetProgress registers a channel to receive progress events during the next UpAll, UpToVersion,
+// Down or DownToVersion run, which closes it when finished, including when the run fails or has
+// nothing to do. Sends never block, so events are dropped rather than stalling migrations when the
+// channel is full. Since a run applies all of its migrations in one transaction, applied and
+// reverted events become durable only once the run returns without error.
+func (m *Migrator) SetProgress(ch chan<- MigrationProgress) {
+	m.progress = ch
+}
+
+// emit sends a progress event without blocking
+func (m *Migrator) emit(event MigrationEvent, migration *Migration, start time.Time, err error) {
+	if m.progress == nil {
+		return
+	}
+	p := MigrationProgress{
+		Event:       event,
+		Version:     migration.Version,
+		Description: migration.Description,
+		Err:         err,
+	}
+	if event != MigrationStarted {
+		p.Duration = time.Since(start)
+	}
+	select {
+	case m.progress <- p:
+	default:
+	}
+}
+
+// closeProgress closes and unregisters the progress channel at the end of a run
+func (m *Migrator) closeProgress() {
+	if m.progress == nil {
+		return
+	}
+	close(m.progress)
+	m.progress = nil
+}
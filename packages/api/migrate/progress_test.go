@@ -0,0 +1,91 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// drain collects the events on ch until it is closed, failing the test if it stays open
+func drain(t *testing.T, ch <-chan MigrationProgress) []MigrationProgress {
+	t.Helper()
+	events := make([]MigrationProgress, 0)
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		case <-timeout:
+			t.Fatal("progress channel was not closed")
+		}
+	}
+}
+
+func TestProgressEvents(t *testing.T) {
+	migrator, _ := newTestMigrator(testMigrations())
+	ch := NewProgressChannel()
+	migrator.SetProgress(ch)
+	if err := migrator.UpAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]MigrationEvent, 0)
+	for _, event := range drain(t, ch) {
+		got = append(got, event.Event)
+	}
+	want := []MigrationEvent{
+		MigrationStarted, MigrationApplied,
+		MigrationStarted, MigrationApplied,
+		MigrationStarted, MigrationApplied,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+
+	ch = NewProgressChannel()
+	migrator.SetProgress(ch)
+	if err := migrator.Down(); err != nil {
+		t.Fatal(err)
+	}
+	events := drain(t, ch)
+	if len(events) != 2 || events[1].Event != MigrationReverted || events[1].Version != 3 {
+		t.Errorf("Down events = %+v, want migration 3 started and reverted", events)
+	}
+}
+
+func TestProgressClosedOnEveryExit(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		run  func(*Migrator, *fakeDB) error
+	}{
+		{"UpAll with no migrations", func(m *Migrator, db *fakeDB) error {
+			m.migrations = nil
+			return m.UpAll()
+		}},
+		{"UpAll failing", func(m *Migrator, db *fakeDB) error {
+			db.failOn = "CREATE TABLE beta"
+			return m.UpAll()
+		}},
+		{"Down before initializing", func(m *Migrator, db *fakeDB) error {
+			return m.Down()
+		}},
+		{"Down with nothing applied", func(m *Migrator, db *fakeDB) error {
+			db.initialized = true
+			return m.Down()
+		}},
+		{"DownToVersion at the target", func(m *Migrator, db *fakeDB) error {
+			return m.DownToVersion(0)
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			migrator, db := newTestMigrator(testMigrations())
+			ch := NewProgressChannel()
+			migrator.SetProgress(ch)
+			tt.run(migrator, db)
+			drain(t, ch)
+		})
+	}
+}
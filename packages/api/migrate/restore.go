@@ -0,0 +1,28 @@
+package main
+
+import "fmt"
+
+// RestoreTo reverts to targetVersion like DownToVersion and then checks the result: the recorded
+// version must be exactly targetVersion, which fails when that version was never applied, and the
+// checksums of the remaining applied migrations must match their files. The down migrations have
+// committed by the time the checks run, so a failed check reports a state to fix by hand.
+func (m *Migrator) RestoreTo(targetVersion int) error {
+	if err := m.DownToVersion(targetVersion); err != nil {
+		return err
+	}
+
+	currentVersion, err := m.GetCurrentVersion()
+	if err != nil {
+		return err
+	}
+	if currentVersion != targetVersion {
+		return fmt.Errorf("restored to version %d but the database is at version %d", targetVersion, currentVersion)
+	}
+
+	applied, err := m.VerifyChecksums(targetVersion)
+	if err != nil {
+		return fmt.Errorf("restored to version %d but verification failed: %w", targetVersion, err)
+	}
+	fmt.Printf("Restored to version %d (%d applied migrations verified)\n", targetVersion, applied)
+	return nil
+}
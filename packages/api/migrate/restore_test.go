@@ -0,0 +1,47 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRestoreTo(t *testing.T) {
+	migrator, db := newTestMigrator(testMigrations())
+	if err := migrator.UpAll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.RestoreTo(1); err != nil {
+		t.Fatalf("RestoreTo(1): %v", err)
+	}
+	if got := db.versions(); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("applied versions = %v, want [1]", got)
+	}
+}
+
+func TestRestoreToUnappliedVersion(t *testing.T) {
+	// Migration 2 was skipped, leaving a hole
+	migrator, db := newTestMigrator(testMigrations())
+	if err := migrator.UpAll(); err != nil {
+		t.Fatal(err)
+	}
+	delete(db.applied, 2)
+
+	err := migrator.RestoreTo(2)
+	if err == nil || !strings.Contains(err.Error(), "database is at version 1") {
+		t.Errorf("RestoreTo(2) = %v, want a version mismatch", err)
+	}
+}
+
+func TestRestoreToEditedMigration(t *testing.T) {
+	migrator, _ := newTestMigrator(testMigrations())
+	if err := migrator.UpAll(); err != nil {
+		t.Fatal(err)
+	}
+	migrator.migrations[0].UpSQL = "CREATE TABLE alpha (id BIGINT);"
+
+	err := migrator.RestoreTo(2)
+	if err == nil || !strings.Contains(err.Error(), "verification failed") {
+		t.Errorf("RestoreTo(2) after editing migration 1 = %v, want a verification failure", err)
+	}
+}
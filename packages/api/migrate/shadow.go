@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ShadowResult reports a rehearsal of the pending migrations run by Shadow
+type ShadowResult struct {
+	// Schema is the temporary schema the migrations were rehearsed in
+	Schema string
+	// Tables are the tables cloned into the shadow schema
+	Tables []string
+	// Durations holds how long each rehearsed migration took, keyed by version
+	Durations map[int]time.Duration
+	// Skipped are the pending migrations left out by the tag filter or the server version
+	Skipped []int
+	// Total is how long the whole rehearsal took, including cloning the tables
+	Total time.Duration
+}
+
+// Shadow rehearses the pending migrations against a copy of the real tables without touching them.
+// It creates a temporary schema, clones every table of the current schema except schema_migrations
+// into it with its data (CREATE TABLE ... (LIKE ... INCLUDING ALL) and INSERT ... SELECT), and puts
+// the shadow schema first in search_path so unqualified names resolve to the copies. The pending
+// migrations that pass the tag filter and Postgres version requirements are then applied there, timing
+// each. Everything runs in one transaction that is always rolled back, so nothing is recorded and the
+// shadow schema is gone whether or not the rehearsal succeeds.
+// Only tables are cloned: types, functions, views and sequences are shared with the real schema,
+// cloned foreign keys still reference the real tables, and sequence values used by the rehearsal are
+// not given back by the rollback. Migrations that reference schema-qualified objects (e.g.
+// public.table) escape the shadow schema.
+func (m *Migrator) Shadow() (ShadowResult, error) {
+	ctx := context.Background()
+	result := ShadowResult{
+		Schema:    fmt.Sprintf("shadow_%d", time.Now().UnixNano()),
+		Durations: make(map[int]time.Duration),
+	}
+
+	currentVersion, err := m.GetCurrentVersion()
+	if err != nil {
+		return result, err
+	}
+
+	pending := make([]*Migration, 0)
+	for _, migration := range m.migrations {
+		if migration.Version > currentVersion {
+			pending = append(pending, migration)
+		}
+	}
+	if len(pending) == 0 {
+		fmt.Println("No pending migrations to rehearse")
+		return result, nil
+	}
+
+	serverVersion := 0
+	for _, migration := range pending {
+		if migration.MinPGVersion > 0 {
+			serverVersion, err = m.ServerMajorVersion()
+			if err != nil {
+				return result, fmt.Errorf("failed to determine server version: %w", err)
+			}
+			break
+		}
+	}
+
+	var sourceSchema string
+	if err := m.db.QueryRowContext(ctx, "SELECT current_schema()").Scan(&sourceSchema); err != nil {
+		return result, fmt.Errorf("failed to determine the current schema: %w", err)
+	}
+	result.Tables, err = m.shadowTables(ctx, sourceSchema)
+	if err != nil {
+		return result, err
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	start := time.Now()
+	schema := pq.QuoteIdentifier(result.Schema)
+	if _, err := tx.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		return result, fmt.Errorf("failed to create shadow schema: %w", err)
+	}
+	for _, table := range result.Tables {
+		shadowTable := schema + "." + pq.QuoteIdentifier(table)
+		sourceTable := pq.QuoteIdentifier(sourceSchema) + "." + pq.QuoteIdentifier(table)
+		_, err := tx.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (LIKE %s INCLUDING ALL)", shadowTable, sourceTable))
+		if err == nil {
+			_, err = tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s SELECT * FROM %s", shadowTable, sourceTable))
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to clone table %s into shadow schema: %w", table, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "SET LOCAL search_path TO "+schema+", "+pq.QuoteIdentifier(sourceSchema)); err != nil {
+		return result, fmt.Errorf("failed to set search_path: %w", err)
+	}
+	fmt.Printf("Cloned %d tables into %s\n", len(result.Tables), result.Schema)
+
+	for _, migration := range pending {
+		if !m.matchesTags(migration) {
+			result.Skipped = append(result.Skipped, migration.Version)
+			fmt.Printf("Skipped migration %d: %s (tags: %s)\n",
+				migration.Version, migration.Description, strings.Join(migration.Tags, ","))
+			continue
+		}
+		if migration.MinPGVersion > serverVersion {
+			if !m.skipUnsupported {
+				return result, fmt.Errorf("migration %d (%s) requires Postgres %d but the server is version %d",
+					migration.Version, migration.Description, migration.MinPGVersion, serverVersion)
+			}
+			result.Skipped = append(result.Skipped, migration.Version)
+			fmt.Printf("Skipped migration %d: %s (requires Postgres %d, server is %d)\n",
+				migration.Version, migration.Description, migration.MinPGVersion, serverVersion)
+			continue
+		}
+
+		if migration.LockTimeout > 0 {
+			if _, err := tx.ExecContext(ctx, lockTimeoutSQL(migration.LockTimeout)); err != nil {
+				return result, fmt.Errorf("failed to set lock timeout for migration %d: %w", migration.Version, err)
+			}
+		}
+
+		migrationStart := time.Now()
+		if _, err := tx.ExecContext(ctx, migration.UpSQL); err != nil {
+			return result, fmt.Errorf("failed to apply migration %d (%s) in shadow schema: %w",
+				migration.Version, migration.Description, err)
+		}
+		result.Durations[migration.Version] = time.Since(migrationStart)
+		if migration.LockTimeout > 0 {
+			if _, err := tx.ExecContext(ctx, resetLockTimeoutSQL); err != nil {
+				return result, fmt.Errorf("failed to reset lock timeout after migration %d: %w", migration.Version, err)
+			}
+		}
+		fmt.Printf("Rehearsed migration %d: %s (%s)\n",
+			migration.Version, migration.Description, result.Durations[migration.Version])
+	}
+
+	if _, err := tx.ExecContext(ctx, "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+		return result, fmt.Errorf("failed to drop shadow schema: %w", err)
+	}
+	result.Total = time.Since(start)
+	return result, nil
+}
+
+// shadowTables lists the tables of schema that Shadow clones, leaving out schema_migrations
+func (m *Migrator) shadowTables(ctx context.Context, schema string) ([]string, error) {
+	rows, err := m.db.QueryContext(ctx, `
+        SELECT table_name
+        FROM information_schema.tables
+        WHERE table_schema = $1 AND table_type = 'BASE TABLE' AND table_name <> 'schema_migrations'
+        ORDER BY table_name
+    `, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables to clone: %w", err)
+	}
+	defer rows.Close()
+
+	tables := make([]string, 0)
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
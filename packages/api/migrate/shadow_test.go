@@ -0,0 +1,78 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestShadow(t *testing.T) {
+	migrations := testMigrations()
+	migrations[2].Tags = []string{"seed"}
+	migrations = append(migrations, &Migration{
+		Version:     4,
+		Description: "add_alpha_name",
+		UpSQL:       "ALTER TABLE alpha ADD name TEXT;",
+		LockTimeout: 2 * time.Second,
+	})
+	migrator, db := newTestMigrator(migrations)
+	db.initialized = true
+	db.applied[1] = fakeRecord{version: 1, description: "create_alpha"}
+	db.results["SELECT current_schema()"] = fakeResult{columns: []string{"current_schema"}, rows: [][]any{{"public"}}}
+	db.results["FROM information_schema.tables"] = fakeResult{columns: []string{"table_name"}, rows: [][]any{{"alpha"}}}
+	migrator.SetTagFilter(nil, []string{"seed"})
+
+	result, err := migrator.Shadow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Tables, []string{"alpha"}) || !reflect.DeepEqual(result.Skipped, []int{3}) {
+		t.Errorf("result = %+v, want alpha cloned and migration 3 skipped", result)
+	}
+	if _, ok := result.Durations[4]; !ok || len(result.Durations) != 2 {
+		t.Errorf("durations = %v, want migrations 2 and 4 timed", result.Durations)
+	}
+
+	schema := `"` + result.Schema + `"`
+	ordered := []string{
+		"CREATE SCHEMA " + schema,
+		"CREATE TABLE " + schema + `."alpha" (LIKE "public"."alpha" INCLUDING ALL)`,
+		"INSERT INTO " + schema + `."alpha" SELECT * FROM "public"."alpha"`,
+		"SET LOCAL search_path TO " + schema + `, "public"`,
+		"CREATE TABLE beta",
+		"ALTER TABLE alpha ADD name",
+		resetLockTimeoutSQL,
+		"DROP SCHEMA " + schema + " CASCADE",
+		"ROLLBACK",
+	}
+	last := -1
+	for _, statement := range ordered {
+		index := db.indexOf(statement)
+		if index <= last {
+			t.Fatalf("%q missing or out of order in %q", statement, db.statements)
+		}
+		last = index
+	}
+
+	for _, unexpected := range []string{initializeSQL, "CREATE TABLE alpha", "CREATE TABLE gamma", "INSERT INTO schema_migrations", "COMMIT"} {
+		if db.executed(unexpected) {
+			t.Errorf("shadow ran %q", unexpected)
+		}
+	}
+	if !reflect.DeepEqual(db.versions(), []int{1}) {
+		t.Errorf("shadow changed the recorded versions to %v", db.versions())
+	}
+}
+
+func TestShadowWithNothingPending(t *testing.T) {
+	migrator, db := newTestMigrator(testMigrations()[:1])
+	db.initialized = true
+	db.applied[1] = fakeRecord{version: 1, description: "create_alpha"}
+
+	if _, err := migrator.Shadow(); err != nil {
+		t.Fatal(err)
+	}
+	if db.executed("BEGIN") {
+		t.Error("shadow opened a transaction with nothing pending")
+	}
+}
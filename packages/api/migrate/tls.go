@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// TLSConfig holds the SSL/TLS settings used when connecting to Postgres
+type TLSConfig struct {
+	SSLMode      string
+	RootCertPath string
+	CertPath     string
+	KeyPath      string
+}
+
+// TLSConfigFromEnv reads TLS settings from POSTGRES_SSLMODE, POSTGRES_SSLROOTCERT,
+// POSTGRES_SSLCERT and POSTGRES_SSLKEY
+func TLSConfigFromEnv() TLSConfig {
+	return TLSConfig{
+		SSLMode:      os.Getenv("POSTGRES_SSLMODE"),
+		RootCertPath: os.Getenv("POSTGRES_SSLROOTCERT"),
+		CertPath:     os.Getenv("POSTGRES_SSLCERT"),
+		KeyPath:      os.Getenv("POSTGRES_SSLKEY"),
+	}
+}
+
+// hasCert reports whether any certificate or key path has been provided
+func (c TLSConfig) hasCert() bool {
+	return c.RootCertPath != "" || c.CertPath != "" || c.KeyPath != ""
+}
+
+// Validate checks that the configured certificate files exist and that a
+// client certificate is always paired with its key
+func (c TLSConfig) Validate() error {
+	if (c.CertPath == "") != (c.KeyPath == "") {
+		return fmt.Errorf("client certificate and key must be provided together")
+	}
+	for _, path := range []string{c.RootCertPath, c.CertPath, c.KeyPath} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("tls file %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// params returns the lib/pq connection parameters for this config.
+// sslmode defaults to "require" when a certificate is provided.
+func (c TLSConfig) params() [][2]string {
+	sslMode := c.SSLMode
+	if sslMode == "" && c.hasCert() {
+		sslMode = "require"
+	}
+
+	params := make([][2]string, 0, 4)
+	if sslMode != "" {
+		params = append(params, [2]string{"sslmode", sslMode})
+	}
+	if c.RootCertPath != "" {
+		params = append(params, [2]string{"sslrootcert", c.RootCertPath})
+	}
+	if c.CertPath != "" {
+		params = append(params, [2]string{"sslcert", c.CertPath})
+	}
+	if c.KeyPath != "" {
+		params = append(params, [2]string{"sslkey", c.KeyPath})
+	}
+	return params
+}
+
+// BuildConnStr applies the TLS settings to a connection string, which may be
+// either a postgres:// URL or a space separated key=value DSN. Settings in the
+// config override any already present in the connection string.
+func BuildConnStr(connStr string, c TLSConfig) (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+
+	params := c.params()
+	if len(params) == 0 {
+		return connStr, nil
+	}
+
+	if strings.HasPrefix(connStr, "postgres://") || strings.HasPrefix(connStr, "postgresql://") {
+		u, err := url.Parse(connStr)
+		if err != nil {
+			return "", fmt.Errorf("invalid connection string: %w", err)
+		}
+		query := u.Query()
+		for _, p := range params {
+			query.Set(p[0], p[1])
+		}
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	}
+
+	// Drop any existing keys we are about to set, then append ours
+	overridden := make(map[string]bool, len(params))
+	for _, p := range params {
+		overridden[p[0]] = true
+	}
+	fields := make([]string, 0)
+	for _, field := range strings.Fields(connStr) {
+		key, _, _ := strings.Cut(field, "=")
+		if overridden[key] {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	for _, p := range params {
+		value := strings.ReplaceAll(p[1], `\`, `\\`)
+		value = strings.ReplaceAll(value, `'`, `\'`)
+		fields = append(fields, fmt.Sprintf("%s='%s'", p[0], value))
+	}
+	return strings.Join(fields, " "), nil
+}
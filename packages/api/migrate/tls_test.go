@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeCertFiles creates empty root, cert and key files for Validate to find
+func writeCertFiles(t *testing.T) (root, cert, key string) {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make([]string, 0, 3)
+	for _, name := range []string{"root.crt", "client.crt", "client.key"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return paths[0], paths[1], paths[2]
+}
+
+func TestBuildConnStrURL(t *testing.T) {
+	root, cert, key := writeCertFiles(t)
+	config := TLSConfig{RootCertPath: root, CertPath: cert, KeyPath: key}
+
+	connStr, err := BuildConnStr("postgres://user:pass@db:5432/crushingviz?sslmode=disable", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"sslmode=require", "sslrootcert=", "sslcert=", "sslkey="} {
+		if !strings.Contains(connStr, want) {
+			t.Errorf("%s does not contain %s", connStr, want)
+		}
+	}
+	if strings.Contains(connStr, "sslmode=disable") {
+		t.Errorf("%s kept the overridden sslmode", connStr)
+	}
+}
+
+func TestBuildConnStrKeyValue(t *testing.T) {
+	root, _, _ := writeCertFiles(t)
+	config := TLSConfig{SSLMode: "verify-full", RootCertPath: root}
+
+	connStr, err := BuildConnStr("host=db dbname=crushingviz sslmode=disable", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "host=db dbname=crushingviz sslmode='verify-full' sslrootcert='" + root + "'"
+	if connStr != want {
+		t.Errorf("BuildConnStr = %q, want %q", connStr, want)
+	}
+}
+
+func TestBuildConnStrWithoutTLS(t *testing.T) {
+	const dsn = "host=db dbname=crushingviz"
+	connStr, err := BuildConnStr(dsn, TLSConfig{})
+	if err != nil || connStr != dsn {
+		t.Errorf("BuildConnStr = %q, %v, want the DSN unchanged", connStr, err)
+	}
+}
+
+func TestTLSConfigValidate(t *testing.T) {
+	_, cert, key := writeCertFiles(t)
+	for _, tt := range []struct {
+		name    string
+		config  TLSConfig
+		wantErr bool
+	}{
+		{"cert and key", TLSConfig{CertPath: cert, KeyPath: key}, false},
+		{"cert without key", TLSConfig{CertPath: cert}, true},
+		{"key without cert", TLSConfig{KeyPath: key}, true},
+		{"missing root cert", TLSConfig{RootCertPath: filepath.Join(t.TempDir(), "missing.crt")}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
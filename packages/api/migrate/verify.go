@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ErrReadOnly is returned for any write attempted while the migrator is in verify-only mode
+var ErrReadOnly = errors.New("migrator is in verify-only mode: writes are not allowed")
+
+// readOnlyDB wraps a DB and rejects every write, so verification can run against a read replica
+type readOnlyDB struct {
+	DB
+}
+
+func (r readOnlyDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return nil, ErrReadOnly
+}
+
+func (r readOnlyDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	return nil, ErrReadOnly
+}
+
+// SetVerifyOnly puts the migrator in verify-only mode, where any statement execution or
+// transaction fails with ErrReadOnly instead of reaching the database
+func (m *Migrator) SetVerifyOnly() {
+	if _, ok := m.db.(readOnlyDB); !ok {
+		m.db = readOnlyDB{DB: m.db}
+	}
+}
+
+// VerifyResult is the outcome of a read-only verification
+type VerifyResult struct {
+	CurrentVersion int
+	// Checked is the number of applied migrations whose checksums were verified
+	Checked int
+	// Pending are the loaded migrations newer than the current version that would be applied going up
+	Pending []*Migration
+}
+
+// Verify checks the checksums of applied migrations and lists the pending ones without writing
+// anything. Unlike UpToVersion it doesn't create schema_migrations, so it fails if the table is
+// missing.
+func (m *Migrator) Verify() (VerifyResult, error) {
+	var result VerifyResult
+
+	currentVersion, err := m.GetCurrentVersion()
+	if err != nil {
+		return result, err
+	}
+	result.CurrentVersion = currentVersion
+
+	result.Checked, err = m.VerifyChecksums(currentVersion)
+	if err != nil {
+		return result, err
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version > currentVersion && m.matchesTags(migration) {
+			result.Pending = append(result.Pending, migration)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	migrator, db := newTestMigrator(testMigrations())
+	if err := migrator.UpToVersion(1); err != nil {
+		t.Fatal(err)
+	}
+	statements := len(db.statements)
+
+	migrator.SetVerifyOnly()
+	result, err := migrator.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.CurrentVersion != 1 || result.Checked != 1 {
+		t.Errorf("result = %+v, want version 1 with 1 checked", result)
+	}
+	pending := make([]int, 0)
+	for _, migration := range result.Pending {
+		pending = append(pending, migration.Version)
+	}
+	if !reflect.DeepEqual(pending, []int{2, 3}) {
+		t.Errorf("pending = %v, want [2 3]", pending)
+	}
+	if got := db.statements[statements:]; len(got) != 2 {
+		t.Errorf("Verify ran %q, want only the version and checksum queries", got)
+	}
+}
+
+func TestVerifyOnlyRejectsWrites(t *testing.T) {
+	migrator, db := newTestMigrator(testMigrations())
+	db.initialized = true
+	migrator.SetVerifyOnly()
+	// Setting it twice doesn't double-wrap the database
+	migrator.SetVerifyOnly()
+	if _, ok := migrator.db.(readOnlyDB).DB.(*fakeDB); !ok {
+		t.Fatalf("verify-only database wraps %T", migrator.db.(readOnlyDB).DB)
+	}
+
+	if err := migrator.UpAll(); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("UpAll in verify-only mode = %v, want ErrReadOnly", err)
+	}
+	if err := migrator.DownToVersion(0); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("DownToVersion in verify-only mode = %v, want ErrReadOnly", err)
+	}
+	if len(db.versions()) != 0 || db.executed("BEGIN") {
+		t.Errorf("verify-only mode reached the database: %q", db.statements)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"crushingviz.info/api/types/acled"
+)
+
+// taxonomy writes the event type -> sub-event type grouping from the acled package as TypeScript.
+// The enum union types themselves are generated by tygo into acled.ts, which this file imports.
+func main() {
+	out := flag.String("out", "", "file to write the TypeScript to (defaults to stdout)")
+	flag.Parse()
+
+	source := generate()
+	if *out == "" {
+		os.Stdout.Write(source)
+		return
+	}
+	if err := os.WriteFile(*out, source, 0644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+}
+
+// generate renders the TypeScript source
+func generate() []byte {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by taxonomy. DO NOT EDIT.\n\n")
+	b.WriteString("import type { DisorderType, EventType, SubEventType } from \"./acled\";\n\n")
+
+	b.WriteString("/**\n * The sub-event types belonging to each event type\n */\n")
+	b.WriteString("export const SubEventTypesByEventType: Record<EventType, SubEventType[]> = {\n")
+	for _, eventType := range acled.GetEventTypes() {
+		fmt.Fprintf(&b, "\t%s: [\n", strconv.Quote(string(eventType)))
+		for _, subEventType := range acled.SubEventTypesFor(eventType) {
+			fmt.Fprintf(&b, "\t\t%s,\n", strconv.Quote(string(subEventType)))
+		}
+		b.WriteString("\t],\n")
+	}
+	b.WriteString("};\n\n")
+
+	b.WriteString("/**\n * The disorder type ACLED assigns to each sub-event type\n */\n")
+	b.WriteString("export const DisorderTypeBySubEventType: Record<SubEventType, DisorderType> = {\n")
+	for _, subEventType := range acled.MatchSubEventTypes("") {
+		disorderType, _ := acled.DisorderTypeForSubEventType(subEventType)
+		fmt.Fprintf(&b, "\t%s: %s,\n", strconv.Quote(string(subEventType)), strconv.Quote(string(disorderType)))
+	}
+	b.WriteString("};\n")
+	return b.Bytes()
+}
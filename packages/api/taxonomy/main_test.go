@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strconv"
+	"testing"
+)
+
+// taxonomyConstants parses the acled package source, rather than using its getters, and returns
+// the values of every DisorderType, EventType and SubEventType constant
+func taxonomyConstants(t *testing.T) []string {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "../types/acled/acled.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := make([]string, 0)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			typeName, ok := valueSpec.Type.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			switch typeName.Name {
+			case "DisorderType", "EventType", "SubEventType":
+			default:
+				continue
+			}
+			for _, value := range valueSpec.Values {
+				literal, ok := value.(*ast.BasicLit)
+				if !ok || literal.Kind != token.STRING {
+					continue
+				}
+				unquoted, err := strconv.Unquote(literal.Value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				values = append(values, unquoted)
+			}
+		}
+	}
+	return values
+}
+
+func TestGenerateCoversEveryConstant(t *testing.T) {
+	source := generate()
+	constants := taxonomyConstants(t)
+	if len(constants) == 0 {
+		t.Fatal("found no taxonomy constants in acled.go")
+	}
+	for _, value := range constants {
+		if !bytes.Contains(source, []byte(strconv.Quote(value))) {
+			t.Errorf("generated TypeScript is missing %q", value)
+		}
+	}
+}
+
+func TestGeneratedFileIsCurrent(t *testing.T) {
+	committed, err := os.ReadFile("../../types/taxonomy.ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(committed, generate()) {
+		t.Error("packages/types/taxonomy.ts is stale, run go generate ./types/acled")
+	}
+}
@@ -1,6 +1,11 @@
 package acled
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+//go:generate go run ../../taxonomy -out ../../../types/taxonomy.ts
 
 /* ***************************
  * * ACLED CUSTOM DATA TYPES *
@@ -187,3 +192,85 @@ func GetStrategicDevelopmentsSubEventTypes() []SubEventType {
 		SubEventTypeStrategicOther,
 	}
 }
+
+// SubEventTypesFor returns the sub-event types that belong to an event type, or nil for an unknown event type
+func SubEventTypesFor(eventType EventType) []SubEventType {
+	switch eventType {
+	case EventTypeBattles:
+		return GetBattlesSubEventTypes()
+	case EventTypeProtests:
+		return GetProtestsSubEventTypes()
+	case EventTypeRiots:
+		return GetRiotsSubEventTypes()
+	case EventTypeExplosionsRemoteViolence:
+		return GetExplosionsRemoteViolenceSubEventTypes()
+	case EventTypeViolenceAgainstCivilians:
+		return GetViolenceAgainstCiviliansSubEventTypes()
+	case EventTypeStrategicDevelopments:
+		return GetStrategicDevelopmentsSubEventTypes()
+	}
+	return nil
+}
+
+// EventTypeForSubEventType returns the event type a sub-event type belongs to
+func EventTypeForSubEventType(subEventType SubEventType) (EventType, bool) {
+	for _, eventType := range GetEventTypes() {
+		for _, s := range SubEventTypesFor(eventType) {
+			if s == subEventType {
+				return eventType, true
+			}
+		}
+	}
+	return "", false
+}
+
+// GetEventTypes returns all event types
+func GetEventTypes() []EventType {
+	return []EventType{
+		EventTypeBattles,
+		EventTypeProtests,
+		EventTypeRiots,
+		EventTypeExplosionsRemoteViolence,
+		EventTypeViolenceAgainstCivilians,
+		EventTypeStrategicDevelopments,
+	}
+}
+
+// DisorderTypeForEventType returns the disorder type ACLED assigns to every event of the given type.
+// ok is false for Riots, whose disorder type depends on the sub-event (violent demonstrations are
+// Demonstrations while mob violence is Political violence), and for unknown event types.
+func DisorderTypeForEventType(eventType EventType) (DisorderType, bool) {
+	switch eventType {
+	case EventTypeBattles, EventTypeExplosionsRemoteViolence, EventTypeViolenceAgainstCivilians:
+		return DisorderTypePoliticalViolence, true
+	case EventTypeProtests:
+		return DisorderTypeDemonstrations, true
+	case EventTypeStrategicDevelopments:
+		return DisorderTypeStrategic, true
+	}
+	return "", false
+}
+
+// MatchSubEventTypes returns the sub-event types whose name contains query, ignoring case,
+// in taxonomy order. An empty query matches every sub-event type.
+func MatchSubEventTypes(query string) []SubEventType {
+	query = strings.ToLower(strings.TrimSpace(query))
+	matches := make([]SubEventType, 0)
+	for _, eventType := range GetEventTypes() {
+		for _, subEventType := range SubEventTypesFor(eventType) {
+			if strings.Contains(strings.ToLower(string(subEventType)), query) {
+				matches = append(matches, subEventType)
+			}
+		}
+	}
+	return matches
+}
+
+// DisorderTypeForSubEventType returns the disorder type ACLED assigns to events of the given sub-event type
+func DisorderTypeForSubEventType(subEventType SubEventType) (DisorderType, bool) {
+	eventType, ok := EventTypeForSubEventType(subEventType)
+	if !ok {
+		return "", false
+	}
+	return disorderTypeForSubEventType(eventType, subEventType)
+}
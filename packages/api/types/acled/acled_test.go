@@ -0,0 +1,87 @@
+package acled
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchSubEventTypes(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		query string
+		want  []SubEventType
+	}{
+		{"prefix", "peace", []SubEventType{SubEventTypeProtestsPeacefulProtest}},
+		{"mid-string", "  DRONE ", []SubEventType{SubEventTypeExplosionsAirDroneStrike}},
+		{"several in taxonomy order", "protest", []SubEventType{
+			SubEventTypeProtestsExcessiveForceAgainstProtesters,
+			SubEventTypeProtestsProtestWithIntervention,
+			SubEventTypeProtestsPeacefulProtest,
+		}},
+		{"no match", "tsunami", []SubEventType{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchSubEventTypes(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchSubEventTypes(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	all := 0
+	for _, eventType := range GetEventTypes() {
+		all += len(SubEventTypesFor(eventType))
+	}
+	if got := MatchSubEventTypes(""); len(got) != all {
+		t.Errorf("empty query matched %d sub-event types, want all %d", len(got), all)
+	}
+}
+
+func TestDisorderTypeForEventType(t *testing.T) {
+	for _, tt := range []struct {
+		eventType EventType
+		want      DisorderType
+		wantOK    bool
+	}{
+		{EventTypeBattles, DisorderTypePoliticalViolence, true},
+		{EventTypeExplosionsRemoteViolence, DisorderTypePoliticalViolence, true},
+		{EventTypeViolenceAgainstCivilians, DisorderTypePoliticalViolence, true},
+		{EventTypeProtests, DisorderTypeDemonstrations, true},
+		{EventTypeStrategicDevelopments, DisorderTypeStrategic, true},
+		// Riots can be either, depending on the sub-event type
+		{EventTypeRiots, "", false},
+		{EventType("Cyber attacks"), "", false},
+	} {
+		got, ok := DisorderTypeForEventType(tt.eventType)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("DisorderTypeForEventType(%q) = %q, %v, want %q, %v", tt.eventType, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestDisorderTypeForSubEventType(t *testing.T) {
+	for _, tt := range []struct {
+		subEventType SubEventType
+		want         DisorderType
+		wantOK       bool
+	}{
+		{SubEventTypeBattlesArmedClash, DisorderTypePoliticalViolence, true},
+		{SubEventTypeProtestsPeacefulProtest, DisorderTypeDemonstrations, true},
+		{SubEventTypeExplosionsAirDroneStrike, DisorderTypePoliticalViolence, true},
+		// The sub-event type settles the Riots ambiguity
+		{SubEventTypeRiotsViolentDemonstration, DisorderTypeDemonstrations, true},
+		{SubEventTypeRiotsMobViolence, DisorderTypePoliticalViolence, true},
+		{SubEventType("Armed clashes"), "", false},
+	} {
+		got, ok := DisorderTypeForSubEventType(tt.subEventType)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("DisorderTypeForSubEventType(%q) = %q, %v, want %q, %v", tt.subEventType, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	// Every sub-event type in the taxonomy has a disorder type
+	for _, subEventType := range MatchSubEventTypes("") {
+		if _, ok := DisorderTypeForSubEventType(subEventType); !ok {
+			t.Errorf("sub-event type %q has no disorder type", subEventType)
+		}
+	}
+}
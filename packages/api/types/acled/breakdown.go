@@ -0,0 +1,94 @@
+package acled
+
+import "fmt"
+
+// SubEventTotal is the summed activity for one sub-event type
+type SubEventTotal struct {
+	SubEventType SubEventType `json:"sub_event_type"`
+	EventCount   uint64       `json:"event_count"`
+	Fatalities   uint64       `json:"fatalities"`
+}
+
+// SubEventBreakdown sums event counts and fatalities per sub-event type of an event type. Every
+// sub-event type of the event type is included, in taxonomy order, with zeros where rows have no data.
+// Rows of other event types are ignored. An unknown event type is an error.
+func SubEventBreakdown(rows []ACLEDWeeklyAggregate, eventType EventType) ([]SubEventTotal, error) {
+	subEventTypes := SubEventTypesFor(eventType)
+	if subEventTypes == nil {
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+
+	totals := make([]SubEventTotal, len(subEventTypes))
+	index := make(map[SubEventType]int, len(subEventTypes))
+	for i, subEventType := range subEventTypes {
+		totals[i].SubEventType = subEventType
+		index[subEventType] = i
+	}
+
+	for _, row := range rows {
+		i, ok := index[row.SubEventType]
+		if !ok || row.EventType != eventType {
+			continue
+		}
+		totals[i].EventCount += row.EventCount
+		totals[i].Fatalities += row.Fatalities
+	}
+	return totals, nil
+}
+
+// SubEventGroup is a custom analytical grouping of sub-event types, e.g. to split Explosions/Remote
+// violence into aerial and ground attacks
+type SubEventGroup struct {
+	Name          string         `json:"name"`
+	SubEventTypes []SubEventType `json:"sub_event_types"`
+}
+
+// SubEventGroupTotal is the summed activity for one SubEventGroup
+type SubEventGroupTotal struct {
+	Group      string `json:"group"`
+	EventCount uint64 `json:"event_count"`
+	Fatalities uint64 `json:"fatalities"`
+}
+
+// ExplosionsDisplayGroups splits Explosions/Remote violence into groups analysts commonly compare
+var ExplosionsDisplayGroups = []SubEventGroup{
+	{Name: "Aerial", SubEventTypes: []SubEventType{
+		SubEventTypeExplosionsAirDroneStrike,
+		SubEventTypeExplosionsShellingArtilleryMissile,
+	}},
+	{Name: "Ground explosives", SubEventTypes: []SubEventType{
+		SubEventTypeExplosionsRemoteExplosiveLandmineIED,
+		SubEventTypeExplosionsGrenade,
+		SubEventTypeExplosionsSuicideBomb,
+	}},
+	{Name: "Chemical", SubEventTypes: []SubEventType{
+		SubEventTypeExplosionsChemicalWeapon,
+	}},
+}
+
+// RegroupSubEvents sums event counts and fatalities into the given groups. Every group is included,
+// in grouping order, with zeros where rows have no data. Rows whose sub-event type isn't in any
+// group are ignored. A sub-event type listed in more than one group is an error.
+func RegroupSubEvents(rows []ACLEDWeeklyAggregate, grouping []SubEventGroup) ([]SubEventGroupTotal, error) {
+	totals := make([]SubEventGroupTotal, len(grouping))
+	index := make(map[SubEventType]int)
+	for i, group := range grouping {
+		totals[i].Group = group.Name
+		for _, subEventType := range group.SubEventTypes {
+			if j, ok := index[subEventType]; ok {
+				return nil, fmt.Errorf("sub-event type %q is in both %q and %q", subEventType, grouping[j].Name, group.Name)
+			}
+			index[subEventType] = i
+		}
+	}
+
+	for _, row := range rows {
+		i, ok := index[row.SubEventType]
+		if !ok {
+			continue
+		}
+		totals[i].EventCount += row.EventCount
+		totals[i].Fatalities += row.Fatalities
+	}
+	return totals, nil
+}
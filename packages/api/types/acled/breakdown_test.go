@@ -0,0 +1,69 @@
+package acled
+
+import (
+	"reflect"
+	"testing"
+)
+
+// subEventRow returns a row of the given sub-event type, with its event type filled in
+func subEventRow(subEventType SubEventType, events, fatalities uint64) ACLEDWeeklyAggregate {
+	eventType, _ := EventTypeForSubEventType(subEventType)
+	return ACLEDWeeklyAggregate{EventType: eventType, SubEventType: subEventType, EventCount: events, Fatalities: fatalities}
+}
+
+func TestSubEventBreakdown(t *testing.T) {
+	rows := []ACLEDWeeklyAggregate{
+		subEventRow(SubEventTypeBattlesArmedClash, 4, 10),
+		subEventRow(SubEventTypeBattlesArmedClash, 1, 2),
+		subEventRow(SubEventTypeBattlesGovernmentRegainsTerritory, 2, 0),
+		subEventRow(SubEventTypeProtestsPeacefulProtest, 9, 0),
+	}
+
+	got, err := SubEventBreakdown(rows, EventTypeBattles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SubEventTotal{
+		{SubEventType: SubEventTypeBattlesGovernmentRegainsTerritory, EventCount: 2},
+		{SubEventType: SubEventTypeBattlesNonStateActorOvertakesTerritory},
+		{SubEventType: SubEventTypeBattlesArmedClash, EventCount: 5, Fatalities: 12},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SubEventBreakdown = %+v, want %+v", got, want)
+	}
+
+	if _, err := SubEventBreakdown(rows, EventType("Skirmishes")); err == nil {
+		t.Error("SubEventBreakdown accepted an unknown event type")
+	}
+}
+
+func TestRegroupSubEvents(t *testing.T) {
+	rows := []ACLEDWeeklyAggregate{
+		subEventRow(SubEventTypeExplosionsAirDroneStrike, 3, 5),
+		subEventRow(SubEventTypeExplosionsShellingArtilleryMissile, 2, 1),
+		subEventRow(SubEventTypeExplosionsGrenade, 1, 0),
+		// Not in any group
+		subEventRow(SubEventTypeBattlesArmedClash, 9, 9),
+	}
+
+	totals, err := RegroupSubEvents(rows, ExplosionsDisplayGroups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SubEventGroupTotal{
+		{Group: "Aerial", EventCount: 5, Fatalities: 6},
+		{Group: "Ground explosives", EventCount: 1, Fatalities: 0},
+		{Group: "Chemical"},
+	}
+	if !reflect.DeepEqual(totals, want) {
+		t.Errorf("RegroupSubEvents = %+v, want %+v", totals, want)
+	}
+
+	overlapping := append(ExplosionsDisplayGroups[:1:1], SubEventGroup{
+		Name:          "Drones",
+		SubEventTypes: []SubEventType{SubEventTypeExplosionsAirDroneStrike},
+	})
+	if _, err := RegroupSubEvents(rows, overlapping); err == nil {
+		t.Error("expected an error for a sub-event type in two groups")
+	}
+}
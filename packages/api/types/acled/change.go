@@ -0,0 +1,78 @@
+package acled
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ChangeRank is an area's week-over-week change in a metric
+type ChangeRank struct {
+	AreaID   int     `json:"area_id"`
+	Previous float64 `json:"previous"`
+	Current  float64 `json:"current"`
+	// Change is the percent change from the previous week, e.g. 150 when the value went from 2 to 5
+	Change float64 `json:"change"`
+}
+
+// BiggestChanges ranks areas by the percent change of the metric from the ACLED week before week to
+// week, normalizing week first. It returns the n largest increases, biggest first, and separately
+// the n largest decreases, steepest first, with ties ordered by area ID. Areas whose previous week
+// summed to less than minPrevious are left out so that tiny baselines, such as 1 event becoming 4,
+// don't crowd the lists; areas with nothing the week before have no percent change and are always
+// left out. An area missing from week counts as 0, a -100% change.
+func BiggestChanges(rows []ACLEDWeeklyAggregate, week time.Time, metric Metric, n int, minPrevious float64) (increases, decreases []ChangeRank, err error) {
+	if n < 1 {
+		return nil, nil, fmt.Errorf("n must be at least 1, got %d", n)
+	}
+	current := NormalizeWeek(week)
+	previous := current.AddDate(0, 0, -7)
+
+	changes := make(map[int]*ChangeRank)
+	for _, row := range rows {
+		rowWeek := NormalizeWeek(row.Week)
+		if !rowWeek.Equal(current) && !rowWeek.Equal(previous) {
+			continue
+		}
+		value, err := metric.Value(row)
+		if err != nil {
+			return nil, nil, err
+		}
+		change, ok := changes[row.AreaID()]
+		if !ok {
+			change = &ChangeRank{AreaID: row.AreaID()}
+			changes[row.AreaID()] = change
+		}
+		if rowWeek.Equal(current) {
+			change.Current += value
+		} else {
+			change.Previous += value
+		}
+	}
+
+	increases, decreases = make([]ChangeRank, 0), make([]ChangeRank, 0)
+	for _, change := range changes {
+		if change.Previous <= 0 || change.Previous < minPrevious {
+			continue
+		}
+		change.Change = (change.Current - change.Previous) / change.Previous * 100
+		if change.Change > 0 {
+			increases = append(increases, *change)
+		} else if change.Change < 0 {
+			decreases = append(decreases, *change)
+		}
+	}
+	sort.Slice(increases, func(i, j int) bool {
+		if increases[i].Change != increases[j].Change {
+			return increases[i].Change > increases[j].Change
+		}
+		return increases[i].AreaID < increases[j].AreaID
+	})
+	sort.Slice(decreases, func(i, j int) bool {
+		if decreases[i].Change != decreases[j].Change {
+			return decreases[i].Change < decreases[j].Change
+		}
+		return decreases[i].AreaID < decreases[j].AreaID
+	})
+	return increases[:min(n, len(increases))], decreases[:min(n, len(decreases))], nil
+}
@@ -0,0 +1,64 @@
+package acled
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBiggestChanges(t *testing.T) {
+	previous, current := date(2024, time.March, 2), date(2024, time.March, 9)
+	rows := []ACLEDWeeklyAggregate{
+		// Area 10 spikes from 10 to 40, split over two sub-event rows
+		weekRow(10, previous, 10), weekRow(10, current, 25), weekRow(10, current, 15),
+		// Area 20 collapses from 20 to 2
+		weekRow(20, previous, 20), weekRow(20, current, 2),
+		// Area 30 rises modestly and area 40 falls modestly
+		weekRow(30, previous, 10), weekRow(30, current, 12),
+		weekRow(40, previous, 10), weekRow(40, current, 9),
+		// Area 50 goes quiet, missing from the current week entirely
+		weekRow(50, previous, 8),
+		// Area 60 jumps fivefold from a tiny baseline
+		weekRow(60, previous, 1), weekRow(60, current, 5),
+		// Area 70 is new, with nothing to compare against
+		weekRow(70, current, 30),
+		// Area 80 is unchanged, and its earlier week is out of range
+		weekRow(80, previous, 5), weekRow(80, current, 5), weekRow(80, previous.AddDate(0, 0, -7), 100),
+	}
+
+	// A mid-week date resolves to the week starting on the 9th
+	increases, decreases, err := BiggestChanges(rows, current.AddDate(0, 0, 3), MetricEventCount, 2, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantIncreases := []ChangeRank{
+		{AreaID: 10, Previous: 10, Current: 40, Change: 300},
+		{AreaID: 30, Previous: 10, Current: 12, Change: 20},
+	}
+	wantDecreases := []ChangeRank{
+		{AreaID: 50, Previous: 8, Current: 0, Change: -100},
+		{AreaID: 20, Previous: 20, Current: 2, Change: -90},
+	}
+	if !reflect.DeepEqual(increases, wantIncreases) {
+		t.Errorf("increases = %+v, want %+v", increases, wantIncreases)
+	}
+	if !reflect.DeepEqual(decreases, wantDecreases) {
+		t.Errorf("decreases = %+v, want %+v", decreases, wantDecreases)
+	}
+
+	// Without a minimum the tiny baseline leads
+	increases, _, err = BiggestChanges(rows, current, MetricEventCount, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(increases) != 1 || increases[0].AreaID != 60 {
+		t.Errorf("increases without a minimum = %+v, want area 60", increases)
+	}
+
+	if _, _, err := BiggestChanges(rows, current, MetricEventCount, 0, 5); err == nil {
+		t.Error("expected an error for n of 0")
+	}
+	if _, _, err := BiggestChanges(rows, current, Metric("bogus"), 1, 5); err == nil {
+		t.Error("expected an error for an unknown metric")
+	}
+}
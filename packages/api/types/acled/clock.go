@@ -0,0 +1,24 @@
+package acled
+
+import "time"
+
+// Clock tells the helpers that depend on the current time what it is, so they can be pinned in tests
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is the wall clock
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always reports the same time
+type FixedClock struct {
+	Time time.Time
+}
+
+func (c FixedClock) Now() time.Time {
+	return c.Time
+}
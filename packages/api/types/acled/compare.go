@@ -0,0 +1,75 @@
+package acled
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxCompareAreas is the most areas CompareAreas lines up at once
+const MaxCompareAreas = 10
+
+// AreaComparison is one area's weekly series on the axis shared by every area in a comparison
+type AreaComparison struct {
+	AreaID int           `json:"area_id"`
+	Series []WeeklyPoint `json:"series"`
+	Total  float64       `json:"total"`
+}
+
+// CompareAreas builds a weekly series of the metric for each of the areas, for side by side charts.
+// Every series runs from the earliest to the latest week any of the areas has data for, with 0 in
+// the weeks an area has none, so the series line up point for point. Results follow the order of
+// areaIDs, and rows of other areas are ignored. An empty list, more than MaxCompareAreas areas or a
+// repeated area is an error.
+func CompareAreas(rows []ACLEDWeeklyAggregate, areaIDs []int, metric Metric) ([]AreaComparison, error) {
+	if len(areaIDs) == 0 {
+		return nil, fmt.Errorf("no areas to compare")
+	}
+	if len(areaIDs) > MaxCompareAreas {
+		return nil, fmt.Errorf("cannot compare more than %d areas, got %d", MaxCompareAreas, len(areaIDs))
+	}
+	index := make(map[int]int, len(areaIDs))
+	for i, areaID := range areaIDs {
+		if _, ok := index[areaID]; ok {
+			return nil, fmt.Errorf("area %d is listed more than once", areaID)
+		}
+		index[areaID] = i
+	}
+
+	values := make([]map[time.Time]float64, len(areaIDs))
+	for i := range values {
+		values[i] = make(map[time.Time]float64)
+	}
+	var first, last time.Time
+	for _, row := range rows {
+		i, ok := index[row.AreaID()]
+		if !ok {
+			continue
+		}
+		value, err := metric.Value(row)
+		if err != nil {
+			return nil, err
+		}
+		week := NormalizeWeek(row.Week)
+		values[i][week] += value
+		if first.IsZero() || week.Before(first) {
+			first = week
+		}
+		if week.After(last) {
+			last = week
+		}
+	}
+
+	comparisons := make([]AreaComparison, len(areaIDs))
+	for i, areaID := range areaIDs {
+		comparisons[i] = AreaComparison{AreaID: areaID, Series: []WeeklyPoint{}}
+		if first.IsZero() {
+			continue
+		}
+		it := NewWeekIterator(first, last)
+		for week, ok := it.Next(); ok; week, ok = it.Next() {
+			comparisons[i].Series = append(comparisons[i].Series, WeeklyPoint{Week: week, Value: values[i][week]})
+			comparisons[i].Total += values[i][week]
+		}
+	}
+	return comparisons, nil
+}
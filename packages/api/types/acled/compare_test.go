@@ -0,0 +1,74 @@
+package acled
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompareAreas(t *testing.T) {
+	weeks := consecutiveWeeks(0, 0, 0, 0)
+	rows := []ACLEDWeeklyAggregate{
+		// Area 10 has data in the first and third weeks, twice in the third
+		weekRow(10, weeks[0].Week, 2),
+		weekRow(10, weeks[2].Week, 1),
+		weekRow(10, weeks[2].Week.AddDate(0, 0, 3), 4),
+		// Area 20 only has data in the last week
+		weekRow(20, weeks[3].Week, 6),
+		// Area 40 isn't compared
+		weekRow(40, weeks[1].Week, 100),
+	}
+
+	comparisons, err := CompareAreas(rows, []int{20, 10, 30}, MetricEventCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		areaID int
+		values []float64
+		total  float64
+	}{
+		{20, []float64{0, 0, 0, 6}, 6},
+		{10, []float64{2, 0, 5, 0}, 7},
+		// An area without rows is zero across the shared axis
+		{30, []float64{0, 0, 0, 0}, 0},
+	}
+	if len(comparisons) != len(want) {
+		t.Fatalf("CompareAreas = %+v, want %d areas", comparisons, len(want))
+	}
+	for i, w := range want {
+		got := comparisons[i]
+		if got.AreaID != w.areaID || !reflect.DeepEqual(pointValues(got.Series), w.values) || got.Total != w.total {
+			t.Errorf("comparison %d = area %d, %v, total %v, want area %d, %v, total %v", i,
+				got.AreaID, pointValues(got.Series), got.Total, w.areaID, w.values, w.total)
+		}
+		for j, point := range got.Series {
+			if !point.Week.Equal(weeks[j].Week) {
+				t.Errorf("area %d point %d week = %s, want %s", got.AreaID, j, point.Week, weeks[j].Week)
+			}
+		}
+	}
+
+	if empty, err := CompareAreas(nil, []int{10}, MetricEventCount); err != nil || len(empty[0].Series) != 0 {
+		t.Errorf("CompareAreas without rows = %+v, %v, want an empty series", empty, err)
+	}
+}
+
+func TestCompareAreasRejectsInvalidInput(t *testing.T) {
+	tooMany := make([]int, MaxCompareAreas+1)
+	for i := range tooMany {
+		tooMany[i] = i + 1
+	}
+	for name, areaIDs := range map[string][]int{
+		"no areas":  nil,
+		"too many":  tooMany,
+		"duplicate": {10, 20, 10},
+	} {
+		if _, err := CompareAreas(nil, areaIDs, MetricEventCount); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := CompareAreas([]ACLEDWeeklyAggregate{weekRow(10, date(2024, time.March, 2), 1)}, []int{10}, Metric("bogus")); err == nil {
+		t.Error("expected an error for an unknown metric")
+	}
+}
@@ -0,0 +1,48 @@
+package acled
+
+import (
+	"fmt"
+	"time"
+)
+
+// CompletenessRow compares the areas reporting in an ACLED week with the areas expected to
+type CompletenessRow struct {
+	Week           time.Time `json:"week"`
+	ReportingAreas int       `json:"reporting_areas"`
+	ExpectedAreas  int       `json:"expected_areas"`
+	// Ratio is ReportingAreas / ExpectedAreas, low for weeks that are likely incomplete
+	Ratio float64 `json:"ratio"`
+}
+
+// WeeklyCompleteness returns, for every ACLED week from the week containing from to the week
+// containing to, how many distinct areas have rows that week against expectedAreas, so sparse weeks
+// can be flagged as likely incomplete. expectedAreas is the number of geographic areas at the level
+// the rows were queried at. Weeks without rows are included with no reporting areas, and rows
+// outside the range are ignored.
+func WeeklyCompleteness(rows []ACLEDWeeklyAggregate, expectedAreas int, from, to time.Time) ([]CompletenessRow, error) {
+	if expectedAreas < 1 {
+		return nil, fmt.Errorf("expected areas must be at least 1, got %d", expectedAreas)
+	}
+
+	reporting := make(map[time.Time]map[int]bool)
+	for _, row := range rows {
+		week := NormalizeWeek(row.Week)
+		if reporting[week] == nil {
+			reporting[week] = make(map[int]bool)
+		}
+		reporting[week][row.AreaID()] = true
+	}
+
+	result := make([]CompletenessRow, 0)
+	it := NewWeekIterator(from, to)
+	for week, ok := it.Next(); ok; week, ok = it.Next() {
+		areas := len(reporting[week])
+		result = append(result, CompletenessRow{
+			Week:           week,
+			ReportingAreas: areas,
+			ExpectedAreas:  expectedAreas,
+			Ratio:          float64(areas) / float64(expectedAreas),
+		})
+	}
+	return result, nil
+}
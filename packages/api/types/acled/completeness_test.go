@@ -0,0 +1,43 @@
+package acled
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWeeklyCompleteness(t *testing.T) {
+	first := date(2024, time.March, 2)
+	week := func(n int) time.Time { return first.AddDate(0, 0, 7*n) }
+
+	rows := []ACLEDWeeklyAggregate{
+		// Week 0: all four areas report, area 10 with two sub-event rows
+		weekRow(10, week(0), 1), weekRow(10, week(0), 2), weekRow(20, week(0), 1),
+		weekRow(30, week(0), 1), weekRow(40, week(0), 1),
+		// Week 1: only one area reports
+		weekRow(10, week(1), 3),
+		// Week 2 has no rows, week 3 has three areas
+		weekRow(10, week(3), 1), weekRow(20, week(3), 1), weekRow(30, week(3), 1),
+		// Outside the range
+		weekRow(10, week(9), 1),
+	}
+
+	// The range runs from a Monday to a Wednesday, covering whole weeks 0 to 3
+	got, err := WeeklyCompleteness(rows, 4, week(0).AddDate(0, 0, 2), week(3).AddDate(0, 0, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CompletenessRow{
+		{Week: week(0), ReportingAreas: 4, ExpectedAreas: 4, Ratio: 1},
+		{Week: week(1), ReportingAreas: 1, ExpectedAreas: 4, Ratio: 0.25},
+		{Week: week(2), ReportingAreas: 0, ExpectedAreas: 4, Ratio: 0},
+		{Week: week(3), ReportingAreas: 3, ExpectedAreas: 4, Ratio: 0.75},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WeeklyCompleteness = %+v, want %+v", got, want)
+	}
+
+	if _, err := WeeklyCompleteness(rows, 0, week(0), week(3)); err == nil {
+		t.Error("expected an error with no expected areas")
+	}
+}
@@ -0,0 +1,50 @@
+package acled
+
+import (
+	"sort"
+	"time"
+)
+
+// CoverageRow is the span of ACLED weeks an area has data for
+type CoverageRow struct {
+	AreaID int `json:"area_id"`
+	// FirstWeek and LastWeek are the earliest and latest weeks with any events
+	FirstWeek time.Time `json:"first_week"`
+	LastWeek  time.Time `json:"last_week"`
+	// ActiveWeeks is the number of distinct weeks with any events between them
+	ActiveWeeks int `json:"active_weeks"`
+}
+
+// AreaCoverage returns, per area, the first and last ACLED weeks with at least one event and how
+// many weeks had events, so a data coverage view can show each area's span. Rows without events
+// don't count, so areas that never had any are left out. Areas are keyed by AreaID and ordered by
+// it; names can be joined from the matching GeographicArea.
+func AreaCoverage(rows []ACLEDWeeklyAggregate) []CoverageRow {
+	activeWeeks := make(map[int]map[time.Time]bool)
+	for _, row := range rows {
+		if row.EventCount == 0 {
+			continue
+		}
+		areaID := row.AreaID()
+		if activeWeeks[areaID] == nil {
+			activeWeeks[areaID] = make(map[time.Time]bool)
+		}
+		activeWeeks[areaID][NormalizeWeek(row.Week)] = true
+	}
+
+	result := make([]CoverageRow, 0, len(activeWeeks))
+	for areaID, weeks := range activeWeeks {
+		coverage := CoverageRow{AreaID: areaID, ActiveWeeks: len(weeks)}
+		for week := range weeks {
+			if coverage.FirstWeek.IsZero() || week.Before(coverage.FirstWeek) {
+				coverage.FirstWeek = week
+			}
+			if week.After(coverage.LastWeek) {
+				coverage.LastWeek = week
+			}
+		}
+		result = append(result, coverage)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].AreaID < result[j].AreaID })
+	return result
+}
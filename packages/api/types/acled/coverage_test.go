@@ -0,0 +1,36 @@
+package acled
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAreaCoverage(t *testing.T) {
+	first := date(2024, time.January, 6)
+	week := func(n int) time.Time { return first.AddDate(0, 0, 7*n) }
+
+	rows := []ACLEDWeeklyAggregate{
+		// Area 20: active in weeks 1, 2 and 6, out of order, with a quiet week 8 after its last event
+		weekRow(20, week(6), 2),
+		weekRow(20, week(1), 1),
+		weekRow(20, week(2), 3),
+		weekRow(20, week(8), 0),
+		// Area 10: a single week, reported twice, on a Wednesday
+		weekRow(10, week(4).AddDate(0, 0, 4), 1),
+		weekRow(10, week(4), 5),
+		// Area 30 never has events
+		weekRow(30, week(0), 0),
+	}
+
+	want := []CoverageRow{
+		{AreaID: 10, FirstWeek: week(4), LastWeek: week(4), ActiveWeeks: 1},
+		{AreaID: 20, FirstWeek: week(1), LastWeek: week(6), ActiveWeeks: 3},
+	}
+	if got := AreaCoverage(rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("AreaCoverage = %+v, want %+v", got, want)
+	}
+	if got := AreaCoverage(nil); len(got) != 0 {
+		t.Errorf("AreaCoverage(nil) = %+v, want empty", got)
+	}
+}
@@ -0,0 +1,70 @@
+package acled
+
+import (
+	"fmt"
+	"strings"
+)
+
+// descriptionTemplate holds the phrasing used by Describe for one language
+type descriptionTemplate struct {
+	event        string
+	events       string
+	fatality     string
+	fatalities   string
+	noFatalities string
+	sentence     string
+}
+
+// descriptionTemplates are keyed by base language. sentence takes the event phrase,
+// the fatality phrase, and the week date in that order.
+var descriptionTemplates = map[string]descriptionTemplate{
+	"en": {
+		event:        "%d %s event",
+		events:       "%d %s events",
+		fatality:     "with %d fatality",
+		fatalities:   "with %d fatalities",
+		noFatalities: "with no fatalities",
+		sentence:     "%s %s in the week of %s.",
+	},
+	"es": {
+		event:        "%d evento de tipo %s",
+		events:       "%d eventos de tipo %s",
+		fatality:     "con %d víctima mortal",
+		fatalities:   "con %d víctimas mortales",
+		noFatalities: "sin víctimas mortales",
+		sentence:     "%s %s en la semana del %s.",
+	},
+}
+
+// Describe returns a short English sentence summarizing the aggregate, e.g.
+// "12 Armed clash events with 34 fatalities in the week of 2024-03-02."
+func (a ACLEDWeeklyAggregate) Describe() string {
+	return a.DescribeIn("en")
+}
+
+// DescribeIn returns the Describe sentence in the language given by a tag such as "es" or "es-MX".
+// Unsupported languages fall back to English. Sub-event names are ACLED's English labels.
+func (a ACLEDWeeklyAggregate) DescribeIn(languageTag string) string {
+	language, _, _ := strings.Cut(strings.ToLower(languageTag), "-")
+	t, ok := descriptionTemplates[language]
+	if !ok {
+		t = descriptionTemplates["en"]
+	}
+
+	events := fmt.Sprintf(t.events, a.EventCount, a.SubEventType)
+	if a.EventCount == 1 {
+		events = fmt.Sprintf(t.event, a.EventCount, a.SubEventType)
+	}
+
+	var fatalities string
+	switch a.Fatalities {
+	case 0:
+		fatalities = t.noFatalities
+	case 1:
+		fatalities = fmt.Sprintf(t.fatality, a.Fatalities)
+	default:
+		fatalities = fmt.Sprintf(t.fatalities, a.Fatalities)
+	}
+
+	return fmt.Sprintf(t.sentence, events, fatalities, a.Week.Format("2006-01-02"))
+}
@@ -0,0 +1,43 @@
+package acled
+
+import "testing"
+
+func TestDescribe(t *testing.T) {
+	week := date(2024, 3, 2)
+	for _, tt := range []struct {
+		name       string
+		events     uint64
+		fatalities uint64
+		want       string
+	}{
+		{"plural", 12, 34, "12 Armed clash events with 34 fatalities in the week of 2024-03-02."},
+		{"singular", 1, 1, "1 Armed clash event with 1 fatality in the week of 2024-03-02."},
+		{"no fatalities", 3, 0, "3 Armed clash events with no fatalities in the week of 2024-03-02."},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			a := ACLEDWeeklyAggregate{
+				Week:         week,
+				SubEventType: SubEventTypeBattlesArmedClash,
+				EventCount:   tt.events,
+				Fatalities:   tt.fatalities,
+			}
+			if got := a.Describe(); got != tt.want {
+				t.Errorf("Describe() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeIn(t *testing.T) {
+	a := ACLEDWeeklyAggregate{Week: date(2024, 3, 2), SubEventType: SubEventTypeBattlesArmedClash, EventCount: 1, Fatalities: 0}
+
+	want := "1 evento de tipo Armed clash sin víctimas mortales en la semana del 2024-03-02."
+	for _, tag := range []string{"es", "es-MX", "ES"} {
+		if got := a.DescribeIn(tag); got != want {
+			t.Errorf("DescribeIn(%q) = %q, want %q", tag, got, want)
+		}
+	}
+	if got := a.DescribeIn("fr"); got != a.Describe() {
+		t.Errorf("DescribeIn(unsupported) = %q, want the English %q", got, a.Describe())
+	}
+}
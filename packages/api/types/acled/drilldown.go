@@ -0,0 +1,66 @@
+package acled
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AreaMetric is the summed activity of a geographic area and the areas below it
+type AreaMetric struct {
+	AreaID     int                `json:"area_id"`
+	Name       string             `json:"name"`
+	Type       GeographicAreaType `json:"type"`
+	EventCount uint64             `json:"event_count"`
+	Fatalities uint64             `json:"fatalities"`
+	// PopulationExposure is the largest exposure of any row, since exposure estimates can't be summed
+	PopulationExposure uint64 `json:"population_exposure"`
+}
+
+// DrillDown returns the immediate children of the parent area, ordered by ID, each with the summed
+// activity of the rows recorded against it or any area below it, so clicking a region lists its
+// countries and clicking a country lists its admin1s. Rows outside the parent, or recorded against
+// the parent itself, are left out. A parent without children, such as an admin1, returns just its
+// own activity. An unknown parent, or a row whose area is unknown, is an error.
+func DrillDown(rows []ACLEDWeeklyAggregate, areas []GeographicArea, parentAreaID int) ([]AreaMetric, error) {
+	byID := make(map[int]GeographicArea, len(areas))
+	for _, area := range areas {
+		byID[area.ID] = area
+	}
+	parent, ok := byID[parentAreaID]
+	if !ok {
+		return nil, fmt.Errorf("unknown geographic area %d", parentAreaID)
+	}
+
+	metrics := make([]AreaMetric, 0)
+	for _, area := range areas {
+		if area.ParentID != nil && *area.ParentID == parentAreaID {
+			metrics = append(metrics, AreaMetric{AreaID: area.ID, Name: area.Name, Type: area.Type})
+		}
+	}
+	if len(metrics) == 0 {
+		metrics = append(metrics, AreaMetric{AreaID: parent.ID, Name: parent.Name, Type: parent.Type})
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].AreaID < metrics[j].AreaID })
+	index := make(map[int]int, len(metrics))
+	for i, metric := range metrics {
+		index[metric.AreaID] = i
+	}
+
+	for _, row := range rows {
+		lineage, err := areaLineage(row.AreaID(), byID)
+		if err != nil {
+			return nil, err
+		}
+		for _, area := range lineage {
+			i, ok := index[area.ID]
+			if !ok {
+				continue
+			}
+			metrics[i].EventCount += row.EventCount
+			metrics[i].Fatalities += row.Fatalities
+			metrics[i].PopulationExposure = max(metrics[i].PopulationExposure, row.PopulationExposure)
+			break
+		}
+	}
+	return metrics, nil
+}
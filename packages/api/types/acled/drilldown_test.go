@@ -0,0 +1,63 @@
+package acled
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDrillDown(t *testing.T) {
+	admin1Row := func(areaID int, events, fatalities, exposure uint64) ACLEDWeeklyAggregate {
+		row := weekRow(areaID, date(2024, time.March, 2), events)
+		row.Fatalities, row.PopulationExposure = fatalities, exposure
+		return row
+	}
+	rows := []ACLEDWeeklyAggregate{
+		admin1Row(100, 3, 1, 5000),
+		admin1Row(101, 2, 4, 8000),
+		// Recorded against Kenya itself rather than one of its admin1s
+		countryRow(10, 1, 0, 0),
+		countryRow(20, 6, 9, 1000),
+	}
+
+	for _, tt := range []struct {
+		name     string
+		parentID int
+		want     []AreaMetric
+	}{
+		{"region to countries", 1, []AreaMetric{
+			{AreaID: 10, Name: "Kenya", Type: GeographicAreaTypeCountry, EventCount: 6, Fatalities: 5, PopulationExposure: 8000},
+		}},
+		{"country to admin1s", 10, []AreaMetric{
+			{AreaID: 100, Name: "Nairobi", Type: GeographicAreaTypeAdmin1, EventCount: 3, Fatalities: 1, PopulationExposure: 5000},
+			{AreaID: 101, Name: "Mombasa", Type: GeographicAreaTypeAdmin1, EventCount: 2, Fatalities: 4, PopulationExposure: 8000},
+		}},
+		{"leaf", 101, []AreaMetric{
+			{AreaID: 101, Name: "Mombasa", Type: GeographicAreaTypeAdmin1, EventCount: 2, Fatalities: 4, PopulationExposure: 8000},
+		}},
+		{"country without admin1s", 20, []AreaMetric{
+			{AreaID: 20, Name: "Yemen", Type: GeographicAreaTypeCountry, EventCount: 6, Fatalities: 9, PopulationExposure: 1000},
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DrillDown(rows, testHierarchy(), tt.parentID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("DrillDown(%d) = %+v, want %+v", tt.parentID, got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("child %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	if _, err := DrillDown(rows, testHierarchy(), 999); err == nil {
+		t.Error("expected an error for an unknown parent")
+	}
+	if _, err := DrillDown([]ACLEDWeeklyAggregate{admin1Row(999, 1, 0, 0)}, testHierarchy(), 1); err == nil {
+		t.Error("expected an error for a row in an unknown area")
+	}
+}
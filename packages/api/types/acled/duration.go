@@ -0,0 +1,77 @@
+package acled
+
+import (
+	"sort"
+	"time"
+)
+
+// DurationRow is the longest run of consecutive active ACLED weeks for an area
+type DurationRow struct {
+	AreaID int `json:"area_id"`
+	// Weeks is the number of consecutive weeks in the run
+	Weeks int `json:"weeks"`
+	// Start is the first week of the run
+	Start time.Time `json:"start"`
+	// End is the last week of the run
+	End time.Time `json:"end"`
+}
+
+// AreaID returns the most specific geographic area the aggregate is recorded against
+func (a ACLEDWeeklyAggregate) AreaID() int {
+	if a.Admin1ID != nil {
+		return *a.Admin1ID
+	}
+	if a.CountryID != nil {
+		return *a.CountryID
+	}
+	return a.RegionID
+}
+
+// ConflictDurations finds, per area, the longest run of consecutive ACLED weeks with at least one
+// event. Rows may arrive in any order and may repeat a week (one row per sub-event type). A week
+// without any events breaks a run. When two runs are equally long the earlier one is reported.
+// Results are ordered by run length, longest first, then by area ID.
+func ConflictDurations(rows []ACLEDWeeklyAggregate) []DurationRow {
+	activeWeeks := make(map[int]map[time.Time]bool)
+	for _, row := range rows {
+		if row.EventCount == 0 {
+			continue
+		}
+		areaID := row.AreaID()
+		if activeWeeks[areaID] == nil {
+			activeWeeks[areaID] = make(map[time.Time]bool)
+		}
+		activeWeeks[areaID][NormalizeWeek(row.Week)] = true
+	}
+
+	result := make([]DurationRow, 0, len(activeWeeks))
+	for areaID, weekSet := range activeWeeks {
+		weeks := make([]time.Time, 0, len(weekSet))
+		for week := range weekSet {
+			weeks = append(weeks, week)
+		}
+		sort.Slice(weeks, func(i, j int) bool { return weeks[i].Before(weeks[j]) })
+
+		longest := DurationRow{AreaID: areaID, Weeks: 1, Start: weeks[0], End: weeks[0]}
+		runStart, runLength := weeks[0], 1
+		for i := 1; i < len(weeks); i++ {
+			if weeks[i].Equal(weeks[i-1].AddDate(0, 0, 7)) {
+				runLength++
+			} else {
+				runStart, runLength = weeks[i], 1
+			}
+			if runLength > longest.Weeks {
+				longest = DurationRow{AreaID: areaID, Weeks: runLength, Start: runStart, End: weeks[i]}
+			}
+		}
+		result = append(result, longest)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Weeks != result[j].Weeks {
+			return result[i].Weeks > result[j].Weeks
+		}
+		return result[i].AreaID < result[j].AreaID
+	})
+	return result
+}
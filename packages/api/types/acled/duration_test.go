@@ -0,0 +1,61 @@
+package acled
+
+import (
+	"testing"
+	"time"
+)
+
+// weekRow returns a row for an admin1 area in the ACLED week starting on the given Saturday
+func weekRow(areaID int, week time.Time, events uint64) ACLEDWeeklyAggregate {
+	return ACLEDWeeklyAggregate{RegionID: 1, Admin1ID: &areaID, Week: week, EventCount: events}
+}
+
+func TestConflictDurations(t *testing.T) {
+	first := date(2024, time.January, 6)
+	week := func(n int) time.Time { return first.AddDate(0, 0, 7*n) }
+
+	rows := []ACLEDWeeklyAggregate{
+		// Area 10: a two-week streak, a quiet week, then a longer three-week streak, out of order
+		weekRow(10, week(5), 1),
+		weekRow(10, week(0), 2),
+		weekRow(10, week(1), 1),
+		weekRow(10, week(2), 0),
+		weekRow(10, week(4), 3),
+		weekRow(10, week(3), 1),
+		// A second sub-event row for a week already counted
+		weekRow(10, week(4), 1),
+		// Area 20: two separate single weeks, the earlier is reported
+		weekRow(20, week(7), 1),
+		weekRow(20, week(1), 4),
+		// Area 30 never has events
+		weekRow(30, week(0), 0),
+	}
+
+	got := ConflictDurations(rows)
+	want := []DurationRow{
+		{AreaID: 10, Weeks: 3, Start: week(3), End: week(5)},
+		{AreaID: 20, Weeks: 1, Start: week(1), End: week(1)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ConflictDurations = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].AreaID != want[i].AreaID || got[i].Weeks != want[i].Weeks ||
+			!got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) {
+			t.Errorf("row %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestAreaID(t *testing.T) {
+	country, admin1 := 5, 50
+	if got := (ACLEDWeeklyAggregate{RegionID: 1}).AreaID(); got != 1 {
+		t.Errorf("region-only AreaID = %d, want 1", got)
+	}
+	if got := (ACLEDWeeklyAggregate{RegionID: 1, CountryID: &country}).AreaID(); got != 5 {
+		t.Errorf("country AreaID = %d, want 5", got)
+	}
+	if got := (ACLEDWeeklyAggregate{RegionID: 1, CountryID: &country, Admin1ID: &admin1}).AreaID(); got != 50 {
+		t.Errorf("admin1 AreaID = %d, want 50", got)
+	}
+}
@@ -0,0 +1,100 @@
+package acled
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ACLEDEvent is a single raw ACLED event record, before weekly aggregation
+type ACLEDEvent struct {
+	// EventID is ACLED's identifier for the event (event_id_cnty)
+	EventID string `json:"event_id"`
+
+	// EventDate is the day the event took place
+	EventDate time.Time `json:"event_date"`
+
+	// RegionID is the foreign key referencing the region in the geographic_area table
+	RegionID int `json:"region_id"`
+
+	// CountryID is the foreign key referencing the country in the geographic_area table
+	CountryID *int `json:"country_id,omitempty"`
+
+	// Admin1ID is the foreign key referencing the admin1 area in the geographic_area table
+	Admin1ID *int `json:"admin1_id,omitempty"`
+
+	// Location is the name of the place the event took place
+	Location string `json:"location"`
+
+	// Actor1 and Actor2 are the named actors involved in the event. Actor2 may be empty.
+	Actor1 string `json:"actor1"`
+	Actor2 string `json:"actor2"`
+
+	DisorderType DisorderType `json:"disorder_type"`
+	EventType    EventType    `json:"event_type"`
+	SubEventType SubEventType `json:"sub_event_type"`
+
+	// Fatalities is the reported number of fatalities for the event
+	Fatalities uint64 `json:"fatalities"`
+
+	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude"`
+}
+
+// AggregateEvents rolls raw events up into weekly aggregates keyed like acled_weekly_agg. Each row
+// counts its events, sums their fatalities and takes the mean of their coordinates as its centroid.
+// Raw events carry no population exposure, so it is left at 0. Rows are returned in order of each
+// key's first appearance.
+func AggregateEvents(events []ACLEDEvent) []ACLEDWeeklyAggregate {
+	rows := make([]ACLEDWeeklyAggregate, 0)
+	index := make(map[AggregateKey]int)
+	for _, event := range events {
+		row := ACLEDWeeklyAggregate{
+			Week:         NormalizeWeek(event.EventDate),
+			RegionID:     event.RegionID,
+			CountryID:    event.CountryID,
+			Admin1ID:     event.Admin1ID,
+			DisorderType: event.DisorderType,
+			EventType:    event.EventType,
+			SubEventType: event.SubEventType,
+		}
+		key := row.Key()
+		i, ok := index[key]
+		if !ok {
+			i = len(rows)
+			index[key] = i
+			rows = append(rows, row)
+		}
+		rows[i].EventCount++
+		rows[i].Fatalities += event.Fatalities
+		// Keep a running mean so the centroid is correct after every event
+		n := float64(rows[i].EventCount)
+		rows[i].CentroidLongitude += (event.Longitude - rows[i].CentroidLongitude) / n
+		rows[i].CentroidLatitude += (event.Latitude - rows[i].CentroidLatitude) / n
+	}
+	return rows
+}
+
+// EventsForActor returns the events where actor1 or actor2 matches actor, so they can be passed to
+// AggregateEvents for actor-scoped aggregates. Names are compared case-insensitively with
+// punctuation and repeated spaces ignored, and a partial name matches, e.g. "military forces of
+// sudan" matches "Military Forces of Sudan (2019-)". An empty actor matches every event.
+func EventsForActor(events []ACLEDEvent, actor string) []ACLEDEvent {
+	query := normalizeActor(actor)
+	matches := make([]ACLEDEvent, 0)
+	for _, event := range events {
+		if strings.Contains(normalizeActor(event.Actor1), query) ||
+			(event.Actor2 != "" && strings.Contains(normalizeActor(event.Actor2), query)) {
+			matches = append(matches, event)
+		}
+	}
+	return matches
+}
+
+// normalizeActor lowercases an actor name and reduces punctuation and whitespace to single spaces
+func normalizeActor(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,87 @@
+package acled
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// clash returns an Armed clash event in Nairobi
+func clash(day time.Time, fatalities uint64, lon, lat float64) ACLEDEvent {
+	admin1 := 30
+	return ACLEDEvent{
+		EventDate: day, RegionID: 1, Admin1ID: &admin1,
+		DisorderType: DisorderTypePoliticalViolence, EventType: EventTypeBattles, SubEventType: SubEventTypeBattlesArmedClash,
+		Fatalities: fatalities, Longitude: lon, Latitude: lat,
+	}
+}
+
+func TestAggregateEvents(t *testing.T) {
+	protest := clash(date(2024, time.March, 4), 0, 0, 0)
+	protest.DisorderType, protest.EventType, protest.SubEventType =
+		DisorderTypeDemonstrations, EventTypeProtests, SubEventTypeProtestsPeacefulProtest
+
+	rows := AggregateEvents([]ACLEDEvent{
+		clash(date(2024, time.March, 2), 3, 36.0, -1.0),
+		protest,
+		// Friday, still in the week starting 2024-03-02
+		clash(date(2024, time.March, 8), 1, 37.0, -2.0),
+		clash(date(2024, time.March, 9), 5, 38.0, -3.0),
+	})
+
+	if len(rows) != 3 {
+		t.Fatalf("AggregateEvents returned %d rows, want 3: %+v", len(rows), rows)
+	}
+	first := rows[0]
+	if !first.Week.Equal(date(2024, time.March, 2)) || first.EventCount != 2 || first.Fatalities != 4 {
+		t.Errorf("first week of clashes = %s, %d events, %d fatalities, want 2024-03-02, 2, 4",
+			first.Week.Format("2006-01-02"), first.EventCount, first.Fatalities)
+	}
+	if math.Abs(first.CentroidLongitude-36.5) > 1e-9 || math.Abs(first.CentroidLatitude+1.5) > 1e-9 {
+		t.Errorf("centroid = (%v, %v), want the mean (36.5, -1.5)", first.CentroidLongitude, first.CentroidLatitude)
+	}
+	if *first.Admin1ID != 30 || first.PopulationExposure != 0 {
+		t.Errorf("first row = %+v", first)
+	}
+	if rows[1].SubEventType != SubEventTypeProtestsPeacefulProtest || rows[1].EventCount != 1 {
+		t.Errorf("second row = %+v, want the protest", rows[1])
+	}
+	if !rows[2].Week.Equal(date(2024, time.March, 9)) || rows[2].Fatalities != 5 {
+		t.Errorf("third row = %+v, want the next week's clash", rows[2])
+	}
+	for _, row := range rows {
+		if err := row.Validate(); err != nil {
+			t.Errorf("aggregated row is invalid: %v", err)
+		}
+	}
+}
+
+func TestEventsForActor(t *testing.T) {
+	involving := func(actor1, actor2 string, fatalities uint64) ACLEDEvent {
+		event := clash(date(2024, time.March, 2), fatalities, 36.0, -1.0)
+		event.Actor1, event.Actor2 = actor1, actor2
+		return event
+	}
+	events := []ACLEDEvent{
+		involving("Military Forces of Kenya (2022-)", "Al Shabaab", 1),
+		involving("Al-Shabaab", "", 2),
+		involving("Police Forces of Kenya (2022-)", "Protesters (Kenya)", 4),
+		involving("Unidentified Armed Group (Kenya)", "", 8),
+	}
+
+	matches := EventsForActor(events, "al shabaab")
+	if len(matches) != 2 || matches[0].Actor2 != "Al Shabaab" || matches[1].Actor1 != "Al-Shabaab" {
+		t.Fatalf("EventsForActor(al shabaab) = %+v, want the first two events", matches)
+	}
+	rows := AggregateEvents(matches)
+	if len(rows) != 1 || rows[0].EventCount != 2 || rows[0].Fatalities != 3 {
+		t.Errorf("aggregated actor events = %+v, want 2 events and 3 fatalities", rows)
+	}
+
+	if got := EventsForActor(events, "KENYA"); len(got) != 3 {
+		t.Errorf("EventsForActor(KENYA) matched %d events, want 3", len(got))
+	}
+	if got := EventsForActor(events, ""); len(got) != len(events) {
+		t.Errorf("EventsForActor with no actor matched %d events, want all", len(got))
+	}
+}
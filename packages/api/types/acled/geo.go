@@ -0,0 +1,260 @@
+package acled
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// earthRadiusKm is the mean radius of the earth used for haversine distances
+const earthRadiusKm = 6371.0
+
+// HaversineKm returns the great-circle distance in kilometers between two lon/lat points
+func HaversineKm(lon1, lat1, lon2, lat2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// ring is a closed sequence of [lon, lat] positions
+type ring [][2]float64
+
+// polygon is an outer ring followed by any holes
+type polygon []ring
+
+// geoJSONObject covers the parts of a GeoJSON Feature or Geometry needed to read polygons
+type geoJSONObject struct {
+	Type        string          `json:"type"`
+	Geometry    *geoJSONObject  `json:"geometry"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// polygons decodes the area's GeoJSON into polygons. The GeoJSON may be a Feature or a
+// Polygon/MultiPolygon geometry, held either as raw JSON or as already unmarshaled values.
+func (g GeographicArea) polygons() []polygon {
+	var raw []byte
+	switch v := g.GeoJSON.(type) {
+	case nil:
+		return nil
+	case []byte:
+		raw = v
+	case json.RawMessage:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		raw = b
+	}
+
+	var obj geoJSONObject
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil
+	}
+	if obj.Type == "Feature" && obj.Geometry != nil {
+		obj = *obj.Geometry
+	}
+
+	switch obj.Type {
+	case "Polygon":
+		var p polygon
+		if err := json.Unmarshal(obj.Coordinates, &p); err != nil {
+			return nil
+		}
+		return []polygon{p}
+	case "MultiPolygon":
+		var ps []polygon
+		if err := json.Unmarshal(obj.Coordinates, &ps); err != nil {
+			return nil
+		}
+		return ps
+	}
+	return nil
+}
+
+// contains reports whether the point falls inside the ring using ray casting
+func (r ring) contains(lon, lat float64) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		xi, yi := r[i][0], r[i][1]
+		xj, yj := r[j][0], r[j][1]
+		if (yi > lat) != (yj > lat) && lon < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// ContainsPoint reports whether the point falls inside the area's polygon geometry, excluding holes.
+// Areas without polygon geometry never contain a point.
+func (g GeographicArea) ContainsPoint(lon, lat float64) bool {
+	return polygonsContain(g.polygons(), lon, lat)
+}
+
+// polygonsContain reports whether the point falls inside any of the polygons, excluding holes
+func polygonsContain(polygons []polygon, lon, lat float64) bool {
+	for _, p := range polygons {
+		if len(p) == 0 || !p[0].contains(lon, lat) {
+			continue
+		}
+		inHole := false
+		for _, hole := range p[1:] {
+			if hole.contains(lon, lat) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// Centroid returns the area-weighted centroid of the outer rings of the area's polygon geometry.
+// ok is false when the area has no polygon geometry.
+func (g GeographicArea) Centroid() (lon float64, lat float64, ok bool) {
+	var totalArea, cx, cy float64
+	var sumLon, sumLat float64
+	var count int
+
+	for _, p := range g.polygons() {
+		if len(p) == 0 {
+			continue
+		}
+		outer := p[0]
+		var area, px, py float64
+		for i, j := 0, len(outer)-1; i < len(outer); j, i = i, i+1 {
+			cross := outer[j][0]*outer[i][1] - outer[i][0]*outer[j][1]
+			area += cross
+			px += (outer[j][0] + outer[i][0]) * cross
+			py += (outer[j][1] + outer[i][1]) * cross
+			sumLon += outer[i][0]
+			sumLat += outer[i][1]
+			count++
+		}
+		// The signed area is negative for clockwise rings, so flip those before adding them up or
+		// the parts of a MultiPolygon with mixed windings cancel out
+		if area < 0 {
+			area, px, py = -area, -px, -py
+		}
+		totalArea += area
+		cx += px
+		cy += py
+	}
+
+	if count == 0 {
+		return 0, 0, false
+	}
+	// Degenerate geometry has no area, so fall back to the average vertex
+	if totalArea == 0 {
+		return sumLon / float64(count), sumLat / float64(count), true
+	}
+	return cx / (3 * totalArea), cy / (3 * totalArea), true
+}
+
+// NearestArea finds the area for a point. An area whose polygon contains the point is preferred
+// and returned with a distance of 0. Otherwise the area with the nearest centroid is returned along
+// with the haversine distance to it in km. ok is false when no area has usable geometry.
+func NearestArea(lon, lat float64, areas []GeographicArea) (GeographicArea, float64, bool) {
+	for _, area := range areas {
+		if area.ContainsPoint(lon, lat) {
+			return area, 0, true
+		}
+	}
+
+	var nearest GeographicArea
+	nearestDistance := math.Inf(1)
+	found := false
+	for _, area := range areas {
+		centroidLon, centroidLat, ok := area.Centroid()
+		if !ok {
+			continue
+		}
+		distance := HaversineKm(lon, lat, centroidLon, centroidLat)
+		if distance < nearestDistance {
+			nearest = area
+			nearestDistance = distance
+			found = true
+		}
+	}
+
+	if !found {
+		return GeographicArea{}, 0, false
+	}
+	return nearest, nearestDistance, true
+}
+
+// BoundingBox returns the extent of the area's polygon geometry. ok is false when the area has no
+// polygon geometry.
+func (g GeographicArea) BoundingBox() (minLon, minLat, maxLon, maxLat float64, ok bool) {
+	minLon, minLat = math.Inf(1), math.Inf(1)
+	maxLon, maxLat = math.Inf(-1), math.Inf(-1)
+	for _, p := range g.polygons() {
+		for _, r := range p {
+			for _, position := range r {
+				minLon = math.Min(minLon, position[0])
+				maxLon = math.Max(maxLon, position[0])
+				minLat = math.Min(minLat, position[1])
+				maxLat = math.Max(maxLat, position[1])
+				ok = true
+			}
+		}
+	}
+	if !ok {
+		return 0, 0, 0, 0, false
+	}
+	return minLon, minLat, maxLon, maxLat, true
+}
+
+// DefaultCentroidDecimals is the centroid precision used for API payloads, roughly 1m at the equator
+const DefaultCentroidDecimals = 5
+
+// RoundCentroids returns a copy of rows with centroid coordinates rounded to the given number of
+// decimal places, halves away from zero, to avoid sending spurious precision. The input rows are
+// not modified. A negative decimals leaves coordinates unrounded.
+func RoundCentroids(rows []ACLEDWeeklyAggregate, decimals int) []ACLEDWeeklyAggregate {
+	rounded := make([]ACLEDWeeklyAggregate, len(rows))
+	copy(rounded, rows)
+	if decimals < 0 {
+		return rounded
+	}
+	scale := math.Pow(10, float64(decimals))
+	for i := range rounded {
+		rounded[i].CentroidLongitude = math.Round(rounded[i].CentroidLongitude*scale) / scale
+		rounded[i].CentroidLatitude = math.Round(rounded[i].CentroidLatitude*scale) / scale
+	}
+	return rounded
+}
+
+// ValidateGeometry checks that the area's GeoJSON, if any, is a Polygon or MultiPolygon (or a
+// Feature holding one) whose rings are closed and have at least four positions
+func (g GeographicArea) ValidateGeometry() error {
+	if g.GeoJSON == nil {
+		return nil
+	}
+	polygons := g.polygons()
+	if len(polygons) == 0 {
+		return fmt.Errorf("area %q has geometry that isn't a Polygon or MultiPolygon", g.Name)
+	}
+	for _, p := range polygons {
+		if len(p) == 0 {
+			return fmt.Errorf("area %q has a polygon without rings", g.Name)
+		}
+		for _, r := range p {
+			if len(r) < 4 {
+				return fmt.Errorf("area %q has a ring with %d positions, at least 4 are required", g.Name, len(r))
+			}
+			if r[0] != r[len(r)-1] {
+				return fmt.Errorf("area %q has a ring that isn't closed", g.Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package acled
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// square returns a GeoJSON Polygon for the square with its lower-left corner at (lon, lat)
+func square(lon, lat, size float64) string {
+	return fmt.Sprintf(`{"type":"Polygon","coordinates":[[[%g,%g],[%g,%g],[%g,%g],[%g,%g],[%g,%g]]]}`,
+		lon, lat, lon+size, lat, lon+size, lat+size, lon, lat+size, lon, lat)
+}
+
+func TestNearestArea(t *testing.T) {
+	areas := []GeographicArea{
+		{Name: "No geometry"},
+		{Name: "West", GeoJSON: square(0, 0, 1)},
+		{Name: "East", GeoJSON: square(10, 0, 1)},
+	}
+
+	area, distance, ok := NearestArea(0.5, 0.5, areas)
+	if !ok || area.Name != "West" || distance != 0 {
+		t.Errorf("point inside West = %q, %g, %v, want West at distance 0", area.Name, distance, ok)
+	}
+
+	area, distance, ok = NearestArea(8, 0.5, areas)
+	if !ok || area.Name != "East" {
+		t.Fatalf("point near East = %q, %v, want East", area.Name, ok)
+	}
+	if want := HaversineKm(8, 0.5, 10.5, 0.5); math.Abs(distance-want) > 1e-9 {
+		t.Errorf("distance to East = %g, want %g", distance, want)
+	}
+
+	if _, _, ok := NearestArea(0, 0, areas[:1]); ok {
+		t.Error("NearestArea found an area without geometry")
+	}
+}
+
+func TestHaversineKm(t *testing.T) {
+	// One degree of latitude is about 111.19km on a sphere of radius 6371km
+	if got := HaversineKm(0, 0, 0, 1); math.Abs(got-111.195) > 0.01 {
+		t.Errorf("HaversineKm over one degree = %g, want about 111.195", got)
+	}
+	if got := HaversineKm(36.8, -1.3, 36.8, -1.3); got != 0 {
+		t.Errorf("HaversineKm of a point to itself = %g, want 0", got)
+	}
+}
+
+func TestCentroid(t *testing.T) {
+	for _, tt := range []struct {
+		name             string
+		geoJSON          any
+		wantLon, wantLat float64
+		wantOK           bool
+	}{
+		{"polygon", square(2, 4, 2), 3, 5, true},
+		{"clockwise polygon", `{"type":"Polygon","coordinates":[[[2,4],[2,6],[4,6],[4,4],[2,4]]]}`, 3, 5, true},
+		// A counter-clockwise square of area 4 around (1, 1) and a clockwise one of area 16 around (12, 2)
+		{"mixed windings", `{"type":"MultiPolygon","coordinates":[` +
+			`[[[0,0],[2,0],[2,2],[0,2],[0,0]]],` +
+			`[[[10,0],[10,4],[14,4],[14,0],[10,0]]]]}`, 9.8, 1.8, true},
+		{"degenerate", `{"type":"Polygon","coordinates":[[[0,0],[2,0],[4,0],[0,0]]]}`, 1.5, 0, true},
+		{"no geometry", nil, 0, 0, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			lon, lat, ok := GeographicArea{GeoJSON: tt.geoJSON}.Centroid()
+			if ok != tt.wantOK || math.Abs(lon-tt.wantLon) > 1e-9 || math.Abs(lat-tt.wantLat) > 1e-9 {
+				t.Errorf("Centroid = (%v, %v, %v), want (%v, %v, %v)", lon, lat, ok, tt.wantLon, tt.wantLat, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRoundCentroids(t *testing.T) {
+	rows := []ACLEDWeeklyAggregate{{CentroidLongitude: 36.8219123456, CentroidLatitude: -1.2920659999}}
+
+	rounded := RoundCentroids(rows, DefaultCentroidDecimals)
+	if rounded[0].CentroidLongitude != 36.82191 || rounded[0].CentroidLatitude != -1.29207 {
+		t.Errorf("rounded to 5 decimals = (%v, %v), want (36.82191, -1.29207)",
+			rounded[0].CentroidLongitude, rounded[0].CentroidLatitude)
+	}
+	if rows[0].CentroidLongitude != 36.8219123456 {
+		t.Error("RoundCentroids modified its input")
+	}
+
+	halves := RoundCentroids([]ACLEDWeeklyAggregate{{CentroidLongitude: 2.5, CentroidLatitude: -2.5}}, 0)
+	if halves[0].CentroidLongitude != 3 || halves[0].CentroidLatitude != -3 {
+		t.Errorf("halves rounded to (%v, %v), want away from zero (3, -3)", halves[0].CentroidLongitude, halves[0].CentroidLatitude)
+	}
+
+	if unrounded := RoundCentroids(rows, -1); unrounded[0] != rows[0] {
+		t.Error("a negative precision changed the coordinates")
+	}
+}
+
+func TestValidateGeometry(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		geoJSON any
+		wantErr string
+	}{
+		{"no geometry", nil, ""},
+		{"polygon", square(0, 0, 1), ""},
+		{"feature", `{"type":"Feature","geometry":` + square(0, 0, 1) + `}`, ""},
+		{"multipolygon", `{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,0]]],[[[5,5],[6,5],[6,6],[5,5]]]]}`, ""},
+		{"unclosed ring", `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1]]]}`, "isn't closed"},
+		{"too few points", `{"type":"Polygon","coordinates":[[[0,0],[1,0],[0,0]]]}`, "3 positions"},
+		{"hole with too few points", `{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,0]],[[1,1],[1,1]]]}`, "2 positions"},
+		{"no rings", `{"type":"Polygon","coordinates":[]}`, "without rings"},
+		{"point", `{"type":"Point","coordinates":[0,0]}`, "isn't a Polygon or MultiPolygon"},
+		{"invalid JSON", `{"type":`, "isn't a Polygon or MultiPolygon"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := GeographicArea{Name: "Test", GeoJSON: tt.geoJSON}.ValidateGeometry()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateGeometry = %v, want no error", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateGeometry = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package acled
+
+import "math"
+
+// geocodeCellDegrees is the cell size of the grid index GeocodeBatch builds over area bounding boxes
+const geocodeCellDegrees = 1.0
+
+// areaSpecificity ranks area types so the most specific containing area wins
+var areaSpecificity = map[GeographicAreaType]int{
+	GeographicAreaTypeRegion:  0,
+	GeographicAreaTypeCountry: 1,
+	GeographicAreaTypeAdmin1:  2,
+}
+
+// GeocodeBatch resolves many [lon, lat] points to the area containing each of them. It decodes the
+// areas' geometry and indexes their bounding boxes in a grid once, so each point is only tested
+// against the polygons of areas whose box covers its cell rather than against every area. When
+// several areas contain a point, such as an admin1 and its country, the most specific one is
+// returned, then the earliest in areas. The result has one entry per point, pointing into areas, or
+// nil where no area contains it. Areas without polygon geometry are never matched.
+func GeocodeBatch(points [][2]float64, areas []GeographicArea) []*GeographicArea {
+	grid := make(map[[2]int][]int)
+	polygons := make([][]polygon, len(areas))
+	for i, area := range areas {
+		polygons[i] = area.polygons()
+		minLon, minLat, maxLon, maxLat, ok := area.BoundingBox()
+		if !ok {
+			continue
+		}
+		minX, minY := geocodeCell(minLon, minLat)
+		maxX, maxY := geocodeCell(maxLon, maxLat)
+		for x := minX; x <= maxX; x++ {
+			for y := minY; y <= maxY; y++ {
+				grid[[2]int{x, y}] = append(grid[[2]int{x, y}], i)
+			}
+		}
+	}
+
+	results := make([]*GeographicArea, len(points))
+	for p, point := range points {
+		x, y := geocodeCell(point[0], point[1])
+		for _, i := range grid[[2]int{x, y}] {
+			if results[p] != nil && areaSpecificity[areas[i].Type] <= areaSpecificity[results[p].Type] {
+				continue
+			}
+			if polygonsContain(polygons[i], point[0], point[1]) {
+				results[p] = &areas[i]
+			}
+		}
+	}
+	return results
+}
+
+// geocodeCell returns the grid cell containing a point
+func geocodeCell(lon, lat float64) (int, int) {
+	return int(math.Floor(lon / geocodeCellDegrees)), int(math.Floor(lat / geocodeCellDegrees))
+}
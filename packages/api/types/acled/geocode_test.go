@@ -0,0 +1,40 @@
+package acled
+
+import "testing"
+
+func TestGeocodeBatch(t *testing.T) {
+	areas := []GeographicArea{
+		{Name: "Kenya", Type: GeographicAreaTypeCountry, GeoJSON: square(34, -4, 6)},
+		{Name: "Nairobi", Type: GeographicAreaTypeAdmin1, GeoJSON: square(36.5, -1.5, 0.5)},
+		{Name: "East Africa", Type: GeographicAreaTypeRegion, GeoJSON: square(20, -20, 40)},
+		{Name: "No geometry", Type: GeographicAreaTypeAdmin1},
+	}
+	points := [][2]float64{
+		{36.8, -1.3},  // Nairobi, inside Kenya and East Africa
+		{39.5, -3.0},  // Kenya outside Nairobi
+		{25.0, -10.0}, // Only East Africa
+		{-70.0, 10.0}, // Nowhere
+	}
+	want := []string{"Nairobi", "Kenya", "East Africa", ""}
+
+	results := GeocodeBatch(points, areas)
+	if len(results) != len(points) {
+		t.Fatalf("GeocodeBatch returned %d results for %d points", len(results), len(points))
+	}
+	for i, result := range results {
+		got := ""
+		if result != nil {
+			got = result.Name
+		}
+		if got != want[i] {
+			t.Errorf("point %v = %q, want %q", points[i], got, want[i])
+		}
+		// Each batch result agrees with checking every area one by one
+		if result != nil && !result.ContainsPoint(points[i][0], points[i][1]) {
+			t.Errorf("point %v is not inside %q", points[i], got)
+		}
+	}
+	if results[0] != &areas[1] {
+		t.Error("results don't point into areas")
+	}
+}
@@ -0,0 +1,64 @@
+package acled
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// GridCell is a square lat/lon cell of a density grid with the summed metric of the rows inside it
+type GridCell struct {
+	// X and Y index the cell as floor(lon / cellDegrees) and floor(lat / cellDegrees)
+	X int `json:"x"`
+	Y int `json:"y"`
+	// Longitude and Latitude are the center of the cell
+	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude"`
+	Total     float64 `json:"total"`
+}
+
+// GridDensity sums the metric of each row into the square cell of cellDegrees containing its
+// centroid, for grid heatmaps. Rows at (0, 0) are treated as missing centroids and skipped. Cells
+// without rows are omitted, and cells are ordered south to north, then west to east.
+func GridDensity(rows []ACLEDWeeklyAggregate, cellDegrees float64, metric Metric) ([]GridCell, error) {
+	if cellDegrees <= 0 {
+		return nil, fmt.Errorf("cell size must be positive, got %v", cellDegrees)
+	}
+
+	cells := make(map[[2]int]*GridCell)
+	for _, row := range rows {
+		if row.CentroidLongitude == 0 && row.CentroidLatitude == 0 {
+			continue
+		}
+		value, err := metric.Value(row)
+		if err != nil {
+			return nil, err
+		}
+
+		x := int(math.Floor(row.CentroidLongitude / cellDegrees))
+		y := int(math.Floor(row.CentroidLatitude / cellDegrees))
+		cell, ok := cells[[2]int{x, y}]
+		if !ok {
+			cell = &GridCell{
+				X:         x,
+				Y:         y,
+				Longitude: (float64(x) + 0.5) * cellDegrees,
+				Latitude:  (float64(y) + 0.5) * cellDegrees,
+			}
+			cells[[2]int{x, y}] = cell
+		}
+		cell.Total += value
+	}
+
+	result := make([]GridCell, 0, len(cells))
+	for _, cell := range cells {
+		result = append(result, *cell)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Y != result[j].Y {
+			return result[i].Y < result[j].Y
+		}
+		return result[i].X < result[j].X
+	})
+	return result, nil
+}
@@ -0,0 +1,47 @@
+package acled
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGridDensity(t *testing.T) {
+	located := func(lon, lat float64, events uint64) ACLEDWeeklyAggregate {
+		row := weekRow(10, date(2024, time.March, 2), events)
+		row.CentroidLongitude, row.CentroidLatitude = lon, lat
+		return row
+	}
+	rows := []ACLEDWeeklyAggregate{
+		located(36.2, -1.3, 2),
+		located(36.8, -1.9, 3),
+		located(37.1, -1.5, 1),
+		located(36.5, 0.5, 4),
+		// Missing centroid
+		located(0, 0, 100),
+	}
+
+	cells, err := GridDensity(rows, 1, MetricEventCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []GridCell{
+		{X: 36, Y: -2, Longitude: 36.5, Latitude: -1.5, Total: 5},
+		{X: 37, Y: -2, Longitude: 37.5, Latitude: -1.5, Total: 1},
+		{X: 36, Y: 0, Longitude: 36.5, Latitude: 0.5, Total: 4},
+	}
+	if len(cells) != len(want) {
+		t.Fatalf("GridDensity = %+v, want %+v", cells, want)
+	}
+	for i := range want {
+		if cells[i] != want[i] {
+			t.Errorf("cell %d = %+v, want %+v", i, cells[i], want[i])
+		}
+	}
+
+	if _, err := GridDensity(rows, 0, MetricEventCount); err == nil {
+		t.Error("expected an error for a zero cell size")
+	}
+	if _, err := GridDensity(rows, 1, Metric("bogus")); err == nil {
+		t.Error("expected an error for an unknown metric")
+	}
+}
@@ -0,0 +1,58 @@
+package acled
+
+import (
+	"fmt"
+	"math"
+)
+
+// Z95 is the standard normal quantile for a 95% interval
+const Z95 = 1.96
+
+// RateInterval is a point estimate with a rough uncertainty band, for rendering error bars. The
+// bands are statistical heuristics computed here, not values provided by ACLED.
+type RateInterval struct {
+	Value float64 `json:"value"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// WilsonInterval returns the Wilson score interval for a proportion of successes out of trials,
+// e.g. the share of events that were fatal. It stays within [0, 1] and behaves sensibly for small
+// counts, where the normal approximation doesn't. With no trials the band is [0, 1]. successes
+// must not exceed trials: a proportion above 1 has no interval, so that is an error.
+func WilsonInterval(successes, trials uint64, z float64) (RateInterval, error) {
+	if successes > trials {
+		return RateInterval{}, fmt.Errorf("%d successes out of %d trials", successes, trials)
+	}
+	if trials == 0 {
+		return RateInterval{Value: 0, Lower: 0, Upper: 1}, nil
+	}
+	n := float64(trials)
+	p := float64(successes) / n
+	z2 := z * z
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	margin := z / (1 + z2/n) * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	return RateInterval{
+		Value: p,
+		Lower: math.Max(0, center-margin),
+		Upper: math.Min(1, center+margin),
+	}, nil
+}
+
+// FatalitiesPerEventInterval returns the aggregate's fatalities per event with a score interval
+// that treats fatalities as a Poisson count over its events. The band narrows as the event count
+// grows, so sparse rows get wide error bars. Rows without events have no rate and return ok false.
+func FatalitiesPerEventInterval(a ACLEDWeeklyAggregate, z float64) (interval RateInterval, ok bool) {
+	if a.EventCount == 0 {
+		return RateInterval{}, false
+	}
+	k := float64(a.Fatalities)
+	n := float64(a.EventCount)
+	z2 := z * z
+	margin := z * math.Sqrt(k+z2/4)
+	return RateInterval{
+		Value: k / n,
+		Lower: math.Max(0, (k+z2/2-margin)/n),
+		Upper: (k + z2/2 + margin) / n,
+	}, true
+}
@@ -0,0 +1,52 @@
+package acled
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWilsonInterval(t *testing.T) {
+	interval, err := WilsonInterval(5, 10, Z95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Reference values for 5/10 at 95%
+	if interval.Value != 0.5 || math.Abs(interval.Lower-0.2366) > 1e-4 || math.Abs(interval.Upper-0.7634) > 1e-4 {
+		t.Errorf("WilsonInterval(5, 10) = %+v, want 0.5 in [0.2366, 0.7634]", interval)
+	}
+
+	for _, tt := range []struct{ successes, trials uint64 }{{0, 10}, {10, 10}, {1, 1}} {
+		interval, err := WilsonInterval(tt.successes, tt.trials, Z95)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if interval.Lower < 0 || interval.Upper > 1 || interval.Lower > interval.Value || interval.Value > interval.Upper {
+			t.Errorf("WilsonInterval(%d, %d) = %+v, want a band within [0, 1] around the value", tt.successes, tt.trials, interval)
+		}
+	}
+
+	if interval, err := WilsonInterval(0, 0, Z95); err != nil || interval != (RateInterval{Upper: 1}) {
+		t.Errorf("WilsonInterval(0, 0) = %+v, %v, want [0, 1]", interval, err)
+	}
+	if _, err := WilsonInterval(11, 10, Z95); err == nil {
+		t.Error("expected an error for more successes than trials")
+	}
+}
+
+func TestFatalitiesPerEventInterval(t *testing.T) {
+	row := ACLEDWeeklyAggregate{EventCount: 4, Fatalities: 8}
+	interval, ok := FatalitiesPerEventInterval(row, Z95)
+	if !ok || interval.Value != 2 || interval.Lower >= 2 || interval.Upper <= 2 || interval.Lower < 0 {
+		t.Errorf("FatalitiesPerEventInterval = %+v, %v, want a band around 2", interval, ok)
+	}
+
+	row.EventCount, row.Fatalities = 400, 800
+	narrow, _ := FatalitiesPerEventInterval(row, Z95)
+	if narrow.Upper-narrow.Lower >= interval.Upper-interval.Lower {
+		t.Errorf("band with more events %+v isn't narrower than %+v", narrow, interval)
+	}
+
+	if _, ok := FatalitiesPerEventInterval(ACLEDWeeklyAggregate{}, Z95); ok {
+		t.Error("a row without events has no rate")
+	}
+}
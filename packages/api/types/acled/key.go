@@ -0,0 +1,56 @@
+package acled
+
+import "time"
+
+// AggregateKey is the composite key identifying an acled_weekly_agg row. Nil area IDs are
+// represented as 0 and the week is held in UTC so keys can be compared and used in maps.
+type AggregateKey struct {
+	Week         time.Time    `json:"week"`
+	RegionID     int          `json:"region_id"`
+	CountryID    int          `json:"country_id"`
+	Admin1ID     int          `json:"admin1_id"`
+	DisorderType DisorderType `json:"disorder_type"`
+	EventType    EventType    `json:"event_type"`
+	SubEventType SubEventType `json:"sub_event_type"`
+}
+
+// Key returns the aggregate's composite key
+func (a ACLEDWeeklyAggregate) Key() AggregateKey {
+	key := AggregateKey{
+		Week:         a.Week.UTC(),
+		RegionID:     a.RegionID,
+		DisorderType: a.DisorderType,
+		EventType:    a.EventType,
+		SubEventType: a.SubEventType,
+	}
+	if a.CountryID != nil {
+		key.CountryID = *a.CountryID
+	}
+	if a.Admin1ID != nil {
+		key.Admin1ID = *a.Admin1ID
+	}
+	return key
+}
+
+// MergeDuplicateKeys collapses rows sharing a composite key into one, summing event counts and
+// fatalities and keeping the largest population exposure. The merged row keeps the centroid of the
+// first row with that key, and rows are returned in order of each key's first appearance.
+func MergeDuplicateKeys(rows []ACLEDWeeklyAggregate) []ACLEDWeeklyAggregate {
+	merged := make([]ACLEDWeeklyAggregate, 0, len(rows))
+	index := make(map[AggregateKey]int, len(rows))
+	for _, row := range rows {
+		key := row.Key()
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, row)
+			continue
+		}
+		merged[i].EventCount += row.EventCount
+		merged[i].Fatalities += row.Fatalities
+		if row.PopulationExposure > merged[i].PopulationExposure {
+			merged[i].PopulationExposure = row.PopulationExposure
+		}
+	}
+	return merged
+}
@@ -0,0 +1,45 @@
+package acled
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeDuplicateKeys(t *testing.T) {
+	week := date(2024, time.March, 2)
+	country := 12
+	sameCountry := 12
+	first := ACLEDWeeklyAggregate{
+		Week: week, RegionID: 1, CountryID: &country,
+		EventType: EventTypeBattles, SubEventType: SubEventTypeBattlesArmedClash,
+		EventCount: 3, Fatalities: 5, PopulationExposure: 1000,
+		CentroidLongitude: 36.8, CentroidLatitude: -1.3,
+	}
+	// The same key, with the country ID held in a different pointer and the week in another zone
+	second := first
+	second.CountryID = &sameCountry
+	second.Week = week.In(time.FixedZone("EAT", 3*3600))
+	second.EventCount, second.Fatalities, second.PopulationExposure = 2, 1, 4000
+	second.CentroidLongitude = 40
+	other := first
+	other.SubEventType = SubEventTypeBattlesGovernmentRegainsTerritory
+
+	merged := MergeDuplicateKeys([]ACLEDWeeklyAggregate{first, other, second})
+	if len(merged) != 2 {
+		t.Fatalf("MergeDuplicateKeys returned %d rows, want 2", len(merged))
+	}
+	got := merged[0]
+	if got.EventCount != 5 || got.Fatalities != 6 || got.PopulationExposure != 4000 {
+		t.Errorf("merged metrics = %d events, %d fatalities, %d exposure, want 5, 6, 4000",
+			got.EventCount, got.Fatalities, got.PopulationExposure)
+	}
+	if got.CentroidLongitude != 36.8 {
+		t.Errorf("merged centroid longitude = %v, want the first row's 36.8", got.CentroidLongitude)
+	}
+	if merged[1].SubEventType != other.SubEventType || merged[1].EventCount != 3 {
+		t.Errorf("second row = %+v, want the untouched Government regains territory row", merged[1])
+	}
+	if first.EventCount != 3 {
+		t.Error("MergeDuplicateKeys modified its input")
+	}
+}
@@ -0,0 +1,66 @@
+package acled
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Metric is a numeric field of an ACLEDWeeklyAggregate that can be summarized or mapped
+type Metric string
+
+const (
+	MetricEventCount         Metric = "event_count"
+	MetricFatalities         Metric = "fatalities"
+	MetricPopulationExposure Metric = "population_exposure"
+)
+
+// Value returns the aggregate's value for the metric
+func (m Metric) Value(a ACLEDWeeklyAggregate) (float64, error) {
+	switch m {
+	case MetricEventCount:
+		return float64(a.EventCount), nil
+	case MetricFatalities:
+		return float64(a.Fatalities), nil
+	case MetricPopulationExposure:
+		return float64(a.PopulationExposure), nil
+	}
+	return 0, fmt.Errorf("unknown metric %q", m)
+}
+
+// Percentiles computes the requested percentiles (0-100) of the metric across rows,
+// linearly interpolating between the closest ranks of the sorted values
+func Percentiles(rows []ACLEDWeeklyAggregate, metric Metric, ps []float64) (map[float64]float64, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("cannot compute percentiles of an empty set")
+	}
+	for _, p := range ps {
+		// Written so that NaN, which fails every comparison, is rejected too
+		if !(p >= 0 && p <= 100) {
+			return nil, fmt.Errorf("percentile %v is outside [0, 100]", p)
+		}
+	}
+
+	values := make([]float64, len(rows))
+	for i, row := range rows {
+		value, err := metric.Value(row)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	sort.Float64s(values)
+
+	result := make(map[float64]float64, len(ps))
+	for _, p := range ps {
+		rank := p / 100 * float64(len(values)-1)
+		lower := int(rank)
+		if lower >= len(values)-1 {
+			result[p] = values[len(values)-1]
+			continue
+		}
+		fraction := rank - float64(lower)
+		result[p] = values[lower] + fraction*(values[lower+1]-values[lower])
+	}
+	return result, nil
+}
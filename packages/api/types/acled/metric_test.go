@@ -0,0 +1,49 @@
+package acled
+
+import (
+	"math"
+	"testing"
+)
+
+// eventCounts returns aggregate rows with the given event counts
+func eventCounts(counts ...uint64) []ACLEDWeeklyAggregate {
+	rows := make([]ACLEDWeeklyAggregate, len(counts))
+	for i, count := range counts {
+		rows[i].EventCount = count
+	}
+	return rows
+}
+
+func TestPercentiles(t *testing.T) {
+	rows := eventCounts(40, 10, 30, 20, 50)
+	got, err := Percentiles(rows, MetricEventCount, []float64{0, 25, 50, 90, 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[float64]float64{0: 10, 25: 20, 50: 30, 90: 46, 100: 50}
+	for p, value := range want {
+		if math.Abs(got[p]-value) > 1e-9 {
+			t.Errorf("p%v = %v, want %v", p, got[p], value)
+		}
+	}
+
+	single, err := Percentiles(eventCounts(7), MetricEventCount, []float64{50})
+	if err != nil || single[50] != 7 {
+		t.Errorf("median of one row = %v, %v, want 7", single[50], err)
+	}
+}
+
+func TestPercentilesRejectsInvalidInput(t *testing.T) {
+	rows := eventCounts(1, 2, 3)
+	for _, p := range []float64{-1, 100.5, math.NaN(), math.Inf(1)} {
+		if _, err := Percentiles(rows, MetricEventCount, []float64{p}); err == nil {
+			t.Errorf("Percentiles accepted percentile %v", p)
+		}
+	}
+	if _, err := Percentiles(nil, MetricEventCount, []float64{50}); err == nil {
+		t.Error("Percentiles accepted an empty set")
+	}
+	if _, err := Percentiles(rows, Metric("deaths"), []float64{50}); err == nil {
+		t.Error("Percentiles accepted an unknown metric")
+	}
+}
@@ -0,0 +1,46 @@
+package acled
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// NearbyAggregate is an aggregate within a search radius, with the distance to its centroid
+type NearbyAggregate struct {
+	Aggregate  ACLEDWeeklyAggregate `json:"aggregate"`
+	DistanceKm float64              `json:"distance_km"`
+}
+
+// NearPoint returns the rows whose centroid is within radiusKm of the point, for "what happened
+// near here" queries, ordered by distance and then by week. Rows are first checked against the band
+// of latitudes the radius can reach, so the haversine distance is only computed for rows that might
+// be inside it. Rows at (0, 0) are treated as missing centroids and skipped.
+func NearPoint(rows []ACLEDWeeklyAggregate, lon, lat, radiusKm float64) ([]NearbyAggregate, error) {
+	if !(radiusKm >= 0) {
+		return nil, fmt.Errorf("radius must not be negative, got %v", radiusKm)
+	}
+	// A degree of latitude is the same length everywhere
+	maxLatDelta := radiusKm / earthRadiusKm * 180 / math.Pi
+
+	nearby := make([]NearbyAggregate, 0)
+	for _, row := range rows {
+		if row.CentroidLongitude == 0 && row.CentroidLatitude == 0 {
+			continue
+		}
+		if math.Abs(row.CentroidLatitude-lat) > maxLatDelta {
+			continue
+		}
+		distance := HaversineKm(lon, lat, row.CentroidLongitude, row.CentroidLatitude)
+		if distance <= radiusKm {
+			nearby = append(nearby, NearbyAggregate{Aggregate: row, DistanceKm: distance})
+		}
+	}
+	sort.SliceStable(nearby, func(i, j int) bool {
+		if nearby[i].DistanceKm != nearby[j].DistanceKm {
+			return nearby[i].DistanceKm < nearby[j].DistanceKm
+		}
+		return nearby[i].Aggregate.Week.Before(nearby[j].Aggregate.Week)
+	})
+	return nearby, nil
+}
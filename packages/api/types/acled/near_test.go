@@ -0,0 +1,61 @@
+package acled
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNearPoint(t *testing.T) {
+	located := func(areaID int, week time.Time, lon, lat float64) ACLEDWeeklyAggregate {
+		row := weekRow(areaID, week, 1)
+		row.CentroidLongitude, row.CentroidLatitude = lon, lat
+		return row
+	}
+	march2, march9 := date(2024, time.March, 2), date(2024, time.March, 9)
+	rows := []ACLEDWeeklyAggregate{
+		// About 56 km north
+		located(1, march2, 36.8, -0.8),
+		// About 11 km east, in two weeks
+		located(2, march9, 36.9, -1.3),
+		located(2, march2, 36.9, -1.3),
+		// About 111 km south, outside the latitude band
+		located(3, march2, 36.8, -2.3),
+		// Inside the latitude band but about 222 km west
+		located(4, march2, 34.8, -1.3),
+		// Missing centroid
+		located(5, march2, 0, 0),
+	}
+
+	nearby, err := NearPoint(rows, 36.8, -1.3, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		areaID     int
+		week       time.Time
+		distanceKm float64
+	}{
+		{2, march2, 11.1},
+		{2, march9, 11.1},
+		{1, march2, 55.6},
+	}
+	if len(nearby) != len(want) {
+		t.Fatalf("NearPoint = %+v, want %d rows", nearby, len(want))
+	}
+	for i, w := range want {
+		got := nearby[i]
+		if got.Aggregate.AreaID() != w.areaID || !got.Aggregate.Week.Equal(w.week) || math.Abs(got.DistanceKm-w.distanceKm) > 0.1 {
+			t.Errorf("row %d = area %d, week %s, %.1f km, want area %d, week %s, %.1f km", i,
+				got.Aggregate.AreaID(), got.Aggregate.Week.Format("2006-01-02"), got.DistanceKm,
+				w.areaID, w.week.Format("2006-01-02"), w.distanceKm)
+		}
+	}
+
+	if exact, err := NearPoint(rows, 36.8, -0.8, 0); err != nil || len(exact) != 1 || exact[0].DistanceKm != 0 {
+		t.Errorf("NearPoint with a zero radius = %+v, %v, want only the row at the point", exact, err)
+	}
+	if _, err := NearPoint(rows, 36.8, -1.3, -1); err == nil {
+		t.Error("expected an error for a negative radius")
+	}
+}
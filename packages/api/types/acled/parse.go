@@ -0,0 +1,94 @@
+package acled
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseDisorderType returns the disorder type with the given name, suggesting the closest
+// disorder types when there is none
+func ParseDisorderType(value string) (DisorderType, error) {
+	disorderTypes := []DisorderType{
+		DisorderTypePoliticalViolence,
+		DisorderTypeDemonstrations,
+		DisorderTypeStrategic,
+	}
+	return parseEnum("disorder type", value, disorderTypes)
+}
+
+// ParseEventType returns the event type with the given name, suggesting the closest event types
+// when there is none
+func ParseEventType(value string) (EventType, error) {
+	return parseEnum("event type", value, GetEventTypes())
+}
+
+// ParseSubEventType returns the sub-event type with the given name, suggesting the closest
+// sub-event types when there is none
+func ParseSubEventType(value string) (SubEventType, error) {
+	return parseEnum("sub-event type", value, MatchSubEventTypes(""))
+}
+
+// parseEnum finds value among the valid values of an enum, or returns an error naming the kind of
+// value and any close matches
+func parseEnum[T ~string](kind string, value string, valid []T) (T, error) {
+	candidates := make([]string, len(valid))
+	for i, v := range valid {
+		if string(v) == value {
+			return v, nil
+		}
+		candidates[i] = string(v)
+	}
+
+	suggestions := suggest(value, candidates)
+	if len(suggestions) == 0 {
+		return "", fmt.Errorf("unknown %s %q", kind, value)
+	}
+	for i, suggestion := range suggestions {
+		suggestions[i] = fmt.Sprintf("%q", suggestion)
+	}
+	return "", fmt.Errorf("unknown %s %q; did you mean %s?", kind, value, strings.Join(suggestions, " or "))
+}
+
+// suggest returns the candidates nearest to value by case-insensitive Levenshtein distance, in
+// candidate order. Candidates further than half the length of value are too different to suggest.
+func suggest(value string, candidates []string) []string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	maxDistance := len([]rune(value)) / 2
+
+	best := maxDistance + 1
+	suggestions := make([]string, 0)
+	for _, candidate := range candidates {
+		distance := levenshtein(value, strings.ToLower(candidate))
+		if distance < best {
+			best = distance
+			suggestions = suggestions[:0]
+		}
+		if distance == best {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	return suggestions
+}
+
+// levenshtein returns the number of single rune insertions, deletions and substitutions needed to
+// turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,58 @@
+package acled
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseExactValues(t *testing.T) {
+	if got, err := ParseSubEventType("Armed clash"); err != nil || got != SubEventTypeBattlesArmedClash {
+		t.Errorf("ParseSubEventType(\"Armed clash\") = %q, %v", got, err)
+	}
+	if got, err := ParseEventType("Riots"); err != nil || got != EventTypeRiots {
+		t.Errorf("ParseEventType(\"Riots\") = %q, %v", got, err)
+	}
+	if got, err := ParseDisorderType("Political violence"); err != nil || got != DisorderTypePoliticalViolence {
+		t.Errorf("ParseDisorderType(\"Political violence\") = %q, %v", got, err)
+	}
+}
+
+func TestParseSuggestions(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			"near miss",
+			func() error { _, err := ParseSubEventType("Armed clashes"); return err }(),
+			`unknown sub-event type "Armed clashes"; did you mean "Armed clash"?`,
+		},
+		{
+			"wrong case",
+			func() error { _, err := ParseEventType("protests"); return err }(),
+			`unknown event type "protests"; did you mean "Protests"?`,
+		},
+		{
+			"wildly off",
+			func() error { _, err := ParseDisorderType("Weather"); return err }(),
+			`unknown disorder type "Weather"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err == nil || tt.err.Error() != tt.want {
+				t.Errorf("error = %v, want %q", tt.err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestTies(t *testing.T) {
+	got := suggest("Riot", []string{"Riots", "Protests", "Riota"})
+	if want := []string{"Riots", "Riota"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("suggest = %q, want %q", got, want)
+	}
+	if got := levenshtein("kitten", "sitting"); got != 3 {
+		t.Errorf("levenshtein(kitten, sitting) = %d, want 3", got)
+	}
+}
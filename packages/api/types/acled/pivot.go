@@ -0,0 +1,114 @@
+package acled
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// PivotDimension is a field of an ACLEDWeeklyAggregate that a pivot table can lay out as rows or columns
+type PivotDimension string
+
+const (
+	PivotDimensionArea         PivotDimension = "area"
+	PivotDimensionWeek         PivotDimension = "week"
+	PivotDimensionDisorderType PivotDimension = "disorder_type"
+	PivotDimensionEventType    PivotDimension = "event_type"
+	PivotDimensionSubEventType PivotDimension = "sub_event_type"
+)
+
+// label returns the row's value of the dimension as a pivot table heading
+func (d PivotDimension) label(a ACLEDWeeklyAggregate) (string, error) {
+	switch d {
+	case PivotDimensionArea:
+		return strconv.Itoa(a.AreaID()), nil
+	case PivotDimensionWeek:
+		return NormalizeWeek(a.Week).Format("2006-01-02"), nil
+	case PivotDimensionDisorderType:
+		return string(a.DisorderType), nil
+	case PivotDimensionEventType:
+		return string(a.EventType), nil
+	case PivotDimensionSubEventType:
+		return string(a.SubEventType), nil
+	}
+	return "", fmt.Errorf("unknown pivot dimension %q", d)
+}
+
+// sortLabels orders headings of the dimension: areas by ID, weeks by date and types alphabetically
+func (d PivotDimension) sortLabels(labels []string) {
+	if d != PivotDimensionArea {
+		sort.Strings(labels)
+		return
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		a, _ := strconv.Atoi(labels[i])
+		b, _ := strconv.Atoi(labels[j])
+		return a < b
+	})
+}
+
+// PivotTable is a metric summed over one dimension of the aggregates down the rows and another
+// across the columns, with row and column totals, for spreadsheet-style views
+type PivotTable struct {
+	Rows    []string `json:"rows"`
+	Columns []string `json:"columns"`
+	// Cells holds one slice per row with one value per column, 0 where no aggregate matched both
+	Cells        [][]float64 `json:"cells"`
+	RowTotals    []float64   `json:"row_totals"`
+	ColumnTotals []float64   `json:"column_totals"`
+	Total        float64     `json:"total"`
+}
+
+// Pivot sums the metric of the rows into a table with one row per value of rowDimension and one
+// column per value of columnDimension. Only values present in rows get a row or column. Areas are
+// labelled by AreaID and weeks by the date they start on. An unknown dimension, the same dimension
+// twice or an unknown metric is an error.
+func Pivot(rows []ACLEDWeeklyAggregate, rowDimension, columnDimension PivotDimension, metric Metric) (PivotTable, error) {
+	if rowDimension == columnDimension {
+		return PivotTable{}, fmt.Errorf("cannot pivot %q against itself", rowDimension)
+	}
+	for _, dimension := range []PivotDimension{rowDimension, columnDimension} {
+		if _, err := dimension.label(ACLEDWeeklyAggregate{}); err != nil {
+			return PivotTable{}, err
+		}
+	}
+
+	type cell struct{ row, column string }
+	values := make(map[cell]float64)
+	rowSeen, columnSeen := make(map[string]bool), make(map[string]bool)
+	table := PivotTable{Rows: []string{}, Columns: []string{}}
+	for _, row := range rows {
+		value, err := metric.Value(row)
+		if err != nil {
+			return PivotTable{}, err
+		}
+		rowLabel, _ := rowDimension.label(row)
+		columnLabel, _ := columnDimension.label(row)
+		values[cell{rowLabel, columnLabel}] += value
+		if !rowSeen[rowLabel] {
+			rowSeen[rowLabel] = true
+			table.Rows = append(table.Rows, rowLabel)
+		}
+		if !columnSeen[columnLabel] {
+			columnSeen[columnLabel] = true
+			table.Columns = append(table.Columns, columnLabel)
+		}
+	}
+	rowDimension.sortLabels(table.Rows)
+	columnDimension.sortLabels(table.Columns)
+
+	table.Cells = make([][]float64, len(table.Rows))
+	table.RowTotals = make([]float64, len(table.Rows))
+	table.ColumnTotals = make([]float64, len(table.Columns))
+	for i, rowLabel := range table.Rows {
+		table.Cells[i] = make([]float64, len(table.Columns))
+		for j, columnLabel := range table.Columns {
+			value := values[cell{rowLabel, columnLabel}]
+			table.Cells[i][j] = value
+			table.RowTotals[i] += value
+			table.ColumnTotals[j] += value
+			table.Total += value
+		}
+	}
+	return table, nil
+}
@@ -0,0 +1,64 @@
+package acled
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPivot(t *testing.T) {
+	row := func(areaID int, subEventType SubEventType, fatalities uint64) ACLEDWeeklyAggregate {
+		r := subEventRow(subEventType, 1, fatalities)
+		r.Week, r.RegionID, r.Admin1ID = date(2024, time.March, 2), 1, &areaID
+		return r
+	}
+	rows := []ACLEDWeeklyAggregate{
+		row(100, SubEventTypeBattlesArmedClash, 4),
+		row(20, SubEventTypeRiotsMobViolence, 1),
+		row(100, SubEventTypeBattlesGovernmentRegainsTerritory, 2),
+		row(100, SubEventTypeRiotsMobViolence, 3),
+		row(3, SubEventTypeBattlesArmedClash, 5),
+	}
+
+	table, err := Pivot(rows, PivotDimensionArea, PivotDimensionEventType, MetricFatalities)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PivotTable{
+		// Areas are ordered by ID, not as strings
+		Rows:    []string{"3", "20", "100"},
+		Columns: []string{"Battles", "Riots"},
+		Cells: [][]float64{
+			{5, 0},
+			{0, 1},
+			{6, 3},
+		},
+		RowTotals:    []float64{5, 1, 9},
+		ColumnTotals: []float64{11, 4},
+		Total:        15,
+	}
+	if !reflect.DeepEqual(table, want) {
+		t.Errorf("Pivot = %+v, want %+v", table, want)
+	}
+
+	empty, err := Pivot(nil, PivotDimensionWeek, PivotDimensionSubEventType, MetricEventCount)
+	if err != nil || len(empty.Rows) != 0 || len(empty.Columns) != 0 || empty.Total != 0 {
+		t.Errorf("Pivot without rows = %+v, %v, want an empty table", empty, err)
+	}
+}
+
+func TestPivotRejectsInvalidInput(t *testing.T) {
+	rows := []ACLEDWeeklyAggregate{weekRow(10, date(2024, time.March, 2), 1)}
+	if _, err := Pivot(rows, PivotDimension("actor"), PivotDimensionEventType, MetricEventCount); err == nil {
+		t.Error("expected an error for an unknown row dimension")
+	}
+	if _, err := Pivot(rows, PivotDimensionArea, PivotDimension("actor"), MetricEventCount); err == nil {
+		t.Error("expected an error for an unknown column dimension")
+	}
+	if _, err := Pivot(rows, PivotDimensionArea, PivotDimensionArea, MetricEventCount); err == nil {
+		t.Error("expected an error for pivoting a dimension against itself")
+	}
+	if _, err := Pivot(rows, PivotDimensionArea, PivotDimensionWeek, Metric("bogus")); err == nil {
+		t.Error("expected an error for an unknown metric")
+	}
+}
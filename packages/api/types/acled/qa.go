@@ -0,0 +1,24 @@
+package acled
+
+// QAFlag marks an aggregate whose values look like a data-entry error
+type QAFlag struct {
+	Key AggregateKey `json:"key"`
+	// FatalitiesPerEvent is the ratio that exceeded the threshold
+	FatalitiesPerEvent float64 `json:"fatalities_per_event"`
+}
+
+// FlagSuspiciousRows returns a flag for every row whose fatalities per event exceed
+// maxFatalitiesPerEvent, in input order. Rows without events are skipped.
+func FlagSuspiciousRows(rows []ACLEDWeeklyAggregate, maxFatalitiesPerEvent float64) []QAFlag {
+	flags := make([]QAFlag, 0)
+	for _, row := range rows {
+		if row.EventCount == 0 {
+			continue
+		}
+		ratio := float64(row.Fatalities) / float64(row.EventCount)
+		if ratio > maxFatalitiesPerEvent {
+			flags = append(flags, QAFlag{Key: row.Key(), FatalitiesPerEvent: ratio})
+		}
+	}
+	return flags
+}
@@ -0,0 +1,26 @@
+package acled
+
+import "testing"
+
+func TestFlagSuspiciousRows(t *testing.T) {
+	bad := subEventRow(SubEventTypeBattlesArmedClash, 2, 900)
+	bad.RegionID = 7
+	rows := []ACLEDWeeklyAggregate{
+		subEventRow(SubEventTypeBattlesArmedClash, 10, 30),
+		bad,
+		subEventRow(SubEventTypeExplosionsAirDroneStrike, 0, 40),
+		subEventRow(SubEventTypeExplosionsSuicideBomb, 1, 100),
+	}
+
+	flags := FlagSuspiciousRows(rows, 100)
+	if len(flags) != 1 {
+		t.Fatalf("FlagSuspiciousRows = %+v, want only the 450 per event row", flags)
+	}
+	if flags[0].Key != bad.Key() || flags[0].FatalitiesPerEvent != 450 {
+		t.Errorf("flag = %+v, want key %+v with ratio 450", flags[0], bad.Key())
+	}
+
+	if flags := FlagSuspiciousRows(rows[:1], 100); len(flags) != 0 {
+		t.Errorf("a normal row was flagged: %+v", flags)
+	}
+}
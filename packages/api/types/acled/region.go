@@ -0,0 +1,70 @@
+package acled
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RegionTotal is the summed activity of every area within an ACLED region
+type RegionTotal struct {
+	RegionID   int    `json:"region_id"`
+	Name       string `json:"name"`
+	EventCount uint64 `json:"event_count"`
+	Fatalities uint64 `json:"fatalities"`
+	// PopulationExposure is the largest exposure of any row, since exposure estimates can't be summed
+	PopulationExposure uint64 `json:"population_exposure"`
+}
+
+// areaLineage returns the area with the given ID followed by its ancestors, most specific first. An
+// ID missing from byID, including a missing parent, or a parent cycle is an error.
+func areaLineage(areaID int, byID map[int]GeographicArea) ([]GeographicArea, error) {
+	lineage := make([]GeographicArea, 0, 3)
+	for id := &areaID; id != nil; id = lineage[len(lineage)-1].ParentID {
+		area, ok := byID[*id]
+		if !ok {
+			return nil, fmt.Errorf("unknown geographic area %d", *id)
+		}
+		if len(lineage) == len(byID) {
+			return nil, fmt.Errorf("geographic area %d has a parent cycle", areaID)
+		}
+		lineage = append(lineage, area)
+	}
+	return lineage, nil
+}
+
+// RegionTotals rolls rows up to the region above their area, following ParentID through the
+// hierarchy of areas, for a dashboard showing one number per region. Event counts and fatalities
+// are summed and population exposure is the maximum. Every region in areas is included, ordered by
+// ID, with zeros where rows have no data. A row whose area is unknown or has no region above it is
+// an error.
+func RegionTotals(rows []ACLEDWeeklyAggregate, areas []GeographicArea) ([]RegionTotal, error) {
+	byID := make(map[int]GeographicArea, len(areas))
+	totals := make([]RegionTotal, 0)
+	for _, area := range areas {
+		byID[area.ID] = area
+		if area.Type == GeographicAreaTypeRegion {
+			totals = append(totals, RegionTotal{RegionID: area.ID, Name: area.Name})
+		}
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].RegionID < totals[j].RegionID })
+	index := make(map[int]int, len(totals))
+	for i, total := range totals {
+		index[total.RegionID] = i
+	}
+
+	for _, row := range rows {
+		lineage, err := areaLineage(row.AreaID(), byID)
+		if err != nil {
+			return nil, err
+		}
+		region := lineage[len(lineage)-1]
+		if region.Type != GeographicAreaTypeRegion {
+			return nil, fmt.Errorf("geographic area %d has no region above it", row.AreaID())
+		}
+		total := &totals[index[region.ID]]
+		total.EventCount += row.EventCount
+		total.Fatalities += row.Fatalities
+		total.PopulationExposure = max(total.PopulationExposure, row.PopulationExposure)
+	}
+	return totals, nil
+}
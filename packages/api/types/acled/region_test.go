@@ -0,0 +1,86 @@
+package acled
+
+import (
+	"testing"
+	"time"
+)
+
+// testHierarchy returns two regions, a country in each, and two admin1s in the first country:
+// Eastern Africa (1) > Kenya (10) > Nairobi (100), Mombasa (101) and Middle East (2) > Yemen (20)
+func testHierarchy() []GeographicArea {
+	area := func(id int, name string, areaType GeographicAreaType, parentID int) GeographicArea {
+		a := GeographicArea{ID: id, Name: name, Type: areaType}
+		if parentID != 0 {
+			a.ParentID = &parentID
+		}
+		return a
+	}
+	return []GeographicArea{
+		area(2, "Middle East", GeographicAreaTypeRegion, 0),
+		area(1, "Eastern Africa", GeographicAreaTypeRegion, 0),
+		area(10, "Kenya", GeographicAreaTypeCountry, 1),
+		area(20, "Yemen", GeographicAreaTypeCountry, 2),
+		area(100, "Nairobi", GeographicAreaTypeAdmin1, 10),
+		area(101, "Mombasa", GeographicAreaTypeAdmin1, 10),
+	}
+}
+
+// countryRow returns a row recorded against a country rather than an admin1
+func countryRow(countryID int, events, fatalities, exposure uint64) ACLEDWeeklyAggregate {
+	return ACLEDWeeklyAggregate{CountryID: &countryID, EventCount: events, Fatalities: fatalities, PopulationExposure: exposure}
+}
+
+func TestRegionTotals(t *testing.T) {
+	admin1Row := func(areaID int, events, fatalities, exposure uint64) ACLEDWeeklyAggregate {
+		row := weekRow(areaID, date(2024, time.March, 2), events)
+		row.Fatalities, row.PopulationExposure = fatalities, exposure
+		return row
+	}
+	rows := []ACLEDWeeklyAggregate{
+		admin1Row(100, 3, 1, 5000),
+		admin1Row(101, 2, 4, 8000),
+		admin1Row(100, 1, 0, 2000),
+		countryRow(20, 6, 9, 1000),
+	}
+
+	totals, err := RegionTotals(rows, testHierarchy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RegionTotal{
+		{RegionID: 1, Name: "Eastern Africa", EventCount: 6, Fatalities: 5, PopulationExposure: 8000},
+		{RegionID: 2, Name: "Middle East", EventCount: 6, Fatalities: 9, PopulationExposure: 1000},
+	}
+	if len(totals) != len(want) {
+		t.Fatalf("RegionTotals = %+v, want %+v", totals, want)
+	}
+	for i := range want {
+		if totals[i] != want[i] {
+			t.Errorf("total %d = %+v, want %+v", i, totals[i], want[i])
+		}
+	}
+
+	empty, err := RegionTotals(nil, testHierarchy())
+	if err != nil || len(empty) != 2 || empty[0].EventCount != 0 || empty[1].EventCount != 0 {
+		t.Errorf("RegionTotals without rows = %+v, %v, want two zeroed regions", empty, err)
+	}
+}
+
+func TestRegionTotalsRejectsBrokenHierarchy(t *testing.T) {
+	if _, err := RegionTotals([]ACLEDWeeklyAggregate{weekRow(999, date(2024, time.March, 2), 1)}, testHierarchy()); err == nil {
+		t.Error("expected an error for a row in an unknown area")
+	}
+
+	orphan := testHierarchy()
+	orphan[3].ParentID = nil
+	if _, err := RegionTotals([]ACLEDWeeklyAggregate{countryRow(20, 1, 0, 0)}, orphan); err == nil {
+		t.Error("expected an error for a country without a region")
+	}
+
+	cycle := testHierarchy()
+	kenya := 10
+	cycle[1].ParentID = &kenya
+	if _, err := RegionTotals([]ACLEDWeeklyAggregate{countryRow(10, 1, 0, 0)}, cycle); err == nil {
+		t.Error("expected an error for a parent cycle")
+	}
+}
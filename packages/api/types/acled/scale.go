@@ -0,0 +1,70 @@
+package acled
+
+import (
+	"fmt"
+	"math"
+)
+
+// ScaleMethod is how metric values are normalized for a choropleth color scale
+type ScaleMethod string
+
+const (
+	// ScaleMinMax maps values linearly onto [0, 1] between the smallest and largest value
+	ScaleMinMax ScaleMethod = "min-max"
+	// ScaleZScore expresses values as standard deviations from the mean
+	ScaleZScore ScaleMethod = "z-score"
+)
+
+// ScaleMetric normalizes the metric of each row for a choropleth color scale, returning one value
+// per row in input order. With perRegion false all rows share one global scale, which keeps colors
+// comparable across the map but lets high-intensity regions wash out the rest. With perRegion true
+// each region is scaled on its own, grouping rows by RegionID, which shows variation within a
+// region but means equal colors in different regions no longer mean equal values. Groups whose
+// values are all equal normalize to 0.
+func ScaleMetric(rows []ACLEDWeeklyAggregate, metric Metric, method ScaleMethod, perRegion bool) ([]float64, error) {
+	if method != ScaleMinMax && method != ScaleZScore {
+		return nil, fmt.Errorf("unknown scale method %q", method)
+	}
+
+	values := make([]float64, len(rows))
+	groups := make(map[int][]int)
+	for i, row := range rows {
+		value, err := metric.Value(row)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+		group := 0
+		if perRegion {
+			group = row.RegionID
+		}
+		groups[group] = append(groups[group], i)
+	}
+
+	scaled := make([]float64, len(rows))
+	for _, indexes := range groups {
+		minValue, maxValue := math.Inf(1), math.Inf(-1)
+		var sum float64
+		for _, i := range indexes {
+			minValue = math.Min(minValue, values[i])
+			maxValue = math.Max(maxValue, values[i])
+			sum += values[i]
+		}
+		mean := sum / float64(len(indexes))
+		var variance float64
+		for _, i := range indexes {
+			variance += (values[i] - mean) * (values[i] - mean)
+		}
+		stddev := math.Sqrt(variance / float64(len(indexes)))
+
+		for _, i := range indexes {
+			switch {
+			case method == ScaleMinMax && maxValue > minValue:
+				scaled[i] = (values[i] - minValue) / (maxValue - minValue)
+			case method == ScaleZScore && stddev > 0:
+				scaled[i] = (values[i] - mean) / stddev
+			}
+		}
+	}
+	return scaled, nil
+}
@@ -0,0 +1,52 @@
+package acled
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestScaleMetric(t *testing.T) {
+	inRegion := func(regionID int, events uint64) ACLEDWeeklyAggregate {
+		row := weekRow(10, date(2024, time.March, 2), events)
+		row.RegionID = regionID
+		return row
+	}
+	rows := []ACLEDWeeklyAggregate{
+		inRegion(1, 0), inRegion(1, 5), inRegion(1, 10),
+		inRegion(2, 100), inRegion(2, 100),
+	}
+
+	for _, tt := range []struct {
+		name      string
+		rows      []ACLEDWeeklyAggregate
+		method    ScaleMethod
+		perRegion bool
+		want      []float64
+	}{
+		{"global min-max", rows, ScaleMinMax, false, []float64{0, 0.05, 0.1, 1, 1}},
+		{"per-region min-max", rows, ScaleMinMax, true, []float64{0, 0.5, 1, 0, 0}},
+		{"z-score", []ACLEDWeeklyAggregate{inRegion(1, 1), inRegion(1, 3)}, ScaleZScore, false, []float64{-1, 1}},
+		{"equal values", rows[3:], ScaleZScore, false, []float64{0, 0}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ScaleMetric(tt.rows, MetricEventCount, tt.method, tt.perRegion)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ScaleMetric = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Errorf("ScaleMetric = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+	if _, err := ScaleMetric(rows, MetricEventCount, ScaleMethod("log"), false); err == nil {
+		t.Error("expected an error for an unknown scale method")
+	}
+}
@@ -0,0 +1,127 @@
+package acled
+
+import "time"
+
+// WeeklyPoint is the value of a series for one ACLED week
+type WeeklyPoint struct {
+	Week  time.Time `json:"week"`
+	Value float64   `json:"value"`
+}
+
+// FillWeeks returns the points sorted by week with every ACLED week between the first and last
+// present exactly once. Points are normalized to their week, values sharing a week are summed and
+// missing weeks are filled with 0.
+func FillWeeks(points []WeeklyPoint) []WeeklyPoint {
+	if len(points) == 0 {
+		return []WeeklyPoint{}
+	}
+
+	values := make(map[time.Time]float64, len(points))
+	first, last := NormalizeWeek(points[0].Week), NormalizeWeek(points[0].Week)
+	for _, point := range points {
+		week := NormalizeWeek(point.Week)
+		values[week] += point.Value
+		if week.Before(first) {
+			first = week
+		}
+		if week.After(last) {
+			last = week
+		}
+	}
+
+	filled := make([]WeeklyPoint, 0, len(values))
+	it := NewWeekIterator(first, last)
+	for week, ok := it.Next(); ok; week, ok = it.Next() {
+		filled = append(filled, WeeklyPoint{Week: week, Value: values[week]})
+	}
+	return filled
+}
+
+// CumulativeSeries returns the running total of the points over time, for "to date" charts. The
+// points are zero-filled and sorted with FillWeeks first, so the result has one point per week and,
+// for non-negative values, never decreases.
+func CumulativeSeries(points []WeeklyPoint) []WeeklyPoint {
+	cumulative := FillWeeks(points)
+	var total float64
+	for i := range cumulative {
+		total += cumulative[i].Value
+		cumulative[i].Value = total
+	}
+	return cumulative
+}
+
+// DifferenceSeries returns the week-over-week change of the points, zero-filled with FillWeeks
+// first. Each point holds its week's value minus the previous week's, so the series is one week
+// shorter than the filled input and starts at its second week.
+func DifferenceSeries(points []WeeklyPoint) []WeeklyPoint {
+	return difference(FillWeeks(points))
+}
+
+// AccelerationSeries returns the week-over-week change of the week-over-week change (the second
+// difference) of the points, zero-filled with FillWeeks first. A positive value means activity rose
+// faster, or fell slower, than the week before: a steadily growing conflict has a flat, positive
+// acceleration, and a sustained positive run points to intensification. The series starts at the
+// third week of the filled input.
+func AccelerationSeries(points []WeeklyPoint) []WeeklyPoint {
+	return difference(difference(FillWeeks(points)))
+}
+
+// difference returns the change between consecutive points of an already filled series
+func difference(points []WeeklyPoint) []WeeklyPoint {
+	if len(points) < 2 {
+		return []WeeklyPoint{}
+	}
+	changes := make([]WeeklyPoint, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		changes[i-1] = WeeklyPoint{Week: points[i].Week, Value: points[i].Value - points[i-1].Value}
+	}
+	return changes
+}
+
+// TrendLabel classifies a week's value against the weeks before it
+type TrendLabel string
+
+const (
+	TrendEscalating   TrendLabel = "escalating"
+	TrendDeEscalating TrendLabel = "de-escalating"
+	TrendStable       TrendLabel = "stable"
+	// TrendInsufficientData marks weeks without lookback weeks of history before them
+	TrendInsufficientData TrendLabel = "insufficient_data"
+)
+
+// TrendClassify labels each point by the percent change of its value from the average of the
+// lookback points before it. A change above threshold percent is escalating, below -threshold is
+// de-escalating and anything else is stable. When the trailing average is 0 any positive value is
+// escalating. The first lookback points are labeled TrendInsufficientData. Points should be sorted
+// and zero-filled, as returned by FillWeeks, so that the lookback spans consecutive weeks.
+func TrendClassify(points []WeeklyPoint, lookback int, threshold float64) []TrendLabel {
+	labels := make([]TrendLabel, len(points))
+	var windowSum float64
+	for i, point := range points {
+		if lookback < 1 || i < lookback {
+			labels[i] = TrendInsufficientData
+			windowSum += point.Value
+			continue
+		}
+
+		average := windowSum / float64(lookback)
+		switch {
+		case average == 0 && point.Value > 0:
+			labels[i] = TrendEscalating
+		case average == 0:
+			labels[i] = TrendStable
+		default:
+			change := (point.Value - average) / average * 100
+			if change > threshold {
+				labels[i] = TrendEscalating
+			} else if change < -threshold {
+				labels[i] = TrendDeEscalating
+			} else {
+				labels[i] = TrendStable
+			}
+		}
+
+		windowSum += point.Value - points[i-lookback].Value
+	}
+	return labels
+}
@@ -0,0 +1,119 @@
+package acled
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// consecutiveWeeks returns one point per value for the ACLED weeks starting on 2024-01-06
+func consecutiveWeeks(values ...float64) []WeeklyPoint {
+	points := make([]WeeklyPoint, len(values))
+	for i, value := range values {
+		points[i] = WeeklyPoint{Week: date(2024, time.January, 6).AddDate(0, 0, 7*i), Value: value}
+	}
+	return points
+}
+
+// pointValues returns the values of the points
+func pointValues(points []WeeklyPoint) []float64 {
+	values := make([]float64, len(points))
+	for i, point := range points {
+		values[i] = point.Value
+	}
+	return values
+}
+
+func TestCumulativeSeries(t *testing.T) {
+	input := consecutiveWeeks(2, 0, 3, 1)
+	// Out of order with the zero week missing, and a second point in the last week
+	points := []WeeklyPoint{input[3], input[0], input[2], {Week: input[3].Week.AddDate(0, 0, 2), Value: 4}}
+
+	got := CumulativeSeries(points)
+	if want := []float64{2, 2, 5, 10}; !reflect.DeepEqual(pointValues(got), want) {
+		t.Errorf("CumulativeSeries values = %v, want %v", pointValues(got), want)
+	}
+	for i, point := range got {
+		if !point.Week.Equal(input[i].Week) {
+			t.Errorf("point %d week = %s, want %s", i, point.Week, input[i].Week)
+		}
+	}
+
+	if got := CumulativeSeries(nil); len(got) != 0 {
+		t.Errorf("CumulativeSeries(nil) = %v, want empty", got)
+	}
+}
+
+func TestTrendClassify(t *testing.T) {
+	points := consecutiveWeeks(10, 10, 10, 12, 8, 10, 0, 0, 5)
+	got := TrendClassify(points, 2, 15)
+	want := []TrendLabel{
+		TrendInsufficientData,
+		TrendInsufficientData,
+		TrendStable,       // 10 against 10
+		TrendEscalating,   // 12 against 10, +20%
+		TrendDeEscalating, // 8 against 11, -27%
+		TrendStable,       // 10 against 10
+		TrendDeEscalating, // 0 against 9
+		TrendDeEscalating, // 0 against 5
+		TrendEscalating,   // 5 against a zero average
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TrendClassify = %v, want %v", got, want)
+	}
+
+	quiet := TrendClassify(consecutiveWeeks(0, 0, 0), 2, 15)
+	if quiet[2] != TrendStable {
+		t.Errorf("zero after zero weeks = %q, want stable", quiet[2])
+	}
+
+	for _, label := range TrendClassify(points[:3], 0, 15) {
+		if label != TrendInsufficientData {
+			t.Errorf("label with no lookback = %q, want insufficient data", label)
+		}
+	}
+}
+
+func TestDifferenceSeries(t *testing.T) {
+	// The third week is missing and counts as zero, so 4, 6, _, 2 changes by 2, -6 and 2
+	input := consecutiveWeeks(4, 6, 0, 2)
+	points := []WeeklyPoint{input[3], input[0], input[1]}
+
+	got := DifferenceSeries(points)
+	if want := []float64{2, -6, 2}; !reflect.DeepEqual(pointValues(got), want) {
+		t.Errorf("DifferenceSeries values = %v, want %v", pointValues(got), want)
+	}
+	for i, point := range got {
+		if !point.Week.Equal(input[i+1].Week) {
+			t.Errorf("point %d week = %s, want %s", i, point.Week, input[i+1].Week)
+		}
+	}
+
+	if got := DifferenceSeries(consecutiveWeeks(3)); len(got) != 0 {
+		t.Errorf("DifferenceSeries of one week = %v, want empty", got)
+	}
+}
+
+func TestAccelerationSeries(t *testing.T) {
+	// Steady growth of 2 a week has no acceleration, then the growth jumps to 5 and stops
+	input := consecutiveWeeks(1, 3, 5, 10, 10)
+	points := []WeeklyPoint{input[4], input[1], input[0], input[3], input[2]}
+
+	got := AccelerationSeries(points)
+	if want := []float64{0, 3, -5}; !reflect.DeepEqual(pointValues(got), want) {
+		t.Errorf("AccelerationSeries values = %v, want %v", pointValues(got), want)
+	}
+	if len(got) > 0 && !got[0].Week.Equal(input[2].Week) {
+		t.Errorf("series starts at %s, want the third week %s", got[0].Week, input[2].Week)
+	}
+
+	// A missing week counts as zero: 1, 0, 5 changes by -1 then 5
+	gap := AccelerationSeries([]WeeklyPoint{input[0], input[2]})
+	if want := []float64{6}; !reflect.DeepEqual(pointValues(gap), want) {
+		t.Errorf("AccelerationSeries over a gap = %v, want %v", pointValues(gap), want)
+	}
+
+	if got := AccelerationSeries(consecutiveWeeks(1, 2)); len(got) != 0 {
+		t.Errorf("AccelerationSeries of two weeks = %v, want empty", got)
+	}
+}
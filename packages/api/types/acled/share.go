@@ -0,0 +1,56 @@
+package acled
+
+import "time"
+
+// SharePoint is an event type's share of the events of one ACLED week
+type SharePoint struct {
+	Week time.Time `json:"week"`
+	// Share is the percentage (0-100) of the week's events that were of this event type
+	Share      float64 `json:"share"`
+	EventCount uint64  `json:"event_count"`
+}
+
+// EventShareSeries returns, for every event type, its share of each week's total events, to show
+// how the mix of events shifts over time. Each series covers every ACLED week from the first to the
+// last in rows, in order, so shares of the same week sum to 100. Weeks without any events, including
+// weeks missing from rows, have a share of 0 for every event type.
+func EventShareSeries(rows []ACLEDWeeklyAggregate) map[EventType][]SharePoint {
+	eventTypes := GetEventTypes()
+	series := make(map[EventType][]SharePoint, len(eventTypes))
+	for _, eventType := range eventTypes {
+		series[eventType] = make([]SharePoint, 0)
+	}
+	if len(rows) == 0 {
+		return series
+	}
+
+	counts := make(map[time.Time]map[EventType]uint64)
+	totals := make(map[time.Time]uint64)
+	first, last := NormalizeWeek(rows[0].Week), NormalizeWeek(rows[0].Week)
+	for _, row := range rows {
+		week := NormalizeWeek(row.Week)
+		if counts[week] == nil {
+			counts[week] = make(map[EventType]uint64)
+		}
+		counts[week][row.EventType] += row.EventCount
+		totals[week] += row.EventCount
+		if week.Before(first) {
+			first = week
+		}
+		if week.After(last) {
+			last = week
+		}
+	}
+
+	it := NewWeekIterator(first, last)
+	for week, ok := it.Next(); ok; week, ok = it.Next() {
+		for _, eventType := range eventTypes {
+			point := SharePoint{Week: week, EventCount: counts[week][eventType]}
+			if totals[week] > 0 {
+				point.Share = float64(point.EventCount) / float64(totals[week]) * 100
+			}
+			series[eventType] = append(series[eventType], point)
+		}
+	}
+	return series
+}
@@ -0,0 +1,70 @@
+package acled
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEventShareSeries(t *testing.T) {
+	first := date(2024, time.March, 2)
+	third := first.AddDate(0, 0, 14)
+	protests := func(week time.Time, events uint64) ACLEDWeeklyAggregate {
+		row := subEventRow(SubEventTypeProtestsPeacefulProtest, events, 0)
+		row.Week = week
+		return row
+	}
+	battles := func(week time.Time, events uint64) ACLEDWeeklyAggregate {
+		row := subEventRow(SubEventTypeBattlesArmedClash, events, 0)
+		row.Week = week
+		return row
+	}
+
+	// Protests go from a quarter of events to three quarters, with an empty week between
+	series := EventShareSeries([]ACLEDWeeklyAggregate{
+		battles(third, 2),
+		protests(first, 1),
+		battles(first, 3),
+		protests(third.AddDate(0, 0, 2), 6),
+	})
+
+	if len(series) != len(GetEventTypes()) {
+		t.Errorf("series has %d event types, want all %d", len(series), len(GetEventTypes()))
+	}
+	wantShares := map[EventType][]float64{
+		EventTypeProtests: {25, 0, 75},
+		EventTypeBattles:  {75, 0, 25},
+		EventTypeRiots:    {0, 0, 0},
+	}
+	for eventType, want := range wantShares {
+		points := series[eventType]
+		if len(points) != len(want) {
+			t.Fatalf("%s has %d points, want %d", eventType, len(points), len(want))
+		}
+		for i, share := range want {
+			if !points[i].Week.Equal(first.AddDate(0, 0, 7*i)) {
+				t.Errorf("%s point %d is for week %s", eventType, i, points[i].Week)
+			}
+			if math.Abs(points[i].Share-share) > 1e-9 {
+				t.Errorf("%s share in week %d = %v, want %v", eventType, i, points[i].Share, share)
+			}
+		}
+	}
+	if got := series[EventTypeProtests][2].EventCount; got != 6 {
+		t.Errorf("protest events in the last week = %d, want 6", got)
+	}
+
+	for i := range series[EventTypeProtests] {
+		total := 0.0
+		for _, points := range series {
+			total += points[i].Share
+		}
+		if total != 0 && math.Abs(total-100) > 1e-9 {
+			t.Errorf("shares of week %d sum to %v", i, total)
+		}
+	}
+
+	if empty := EventShareSeries(nil); len(empty[EventTypeBattles]) != 0 {
+		t.Errorf("EventShareSeries(nil) = %v, want empty series", empty)
+	}
+}
@@ -0,0 +1,68 @@
+package acled
+
+// RowChange is a row present in both snapshots whose metrics differ
+type RowChange struct {
+	Key    AggregateKey         `json:"key"`
+	Before ACLEDWeeklyAggregate `json:"before"`
+	After  ACLEDWeeklyAggregate `json:"after"`
+}
+
+// SnapshotDiff describes how a newer download of ACLED aggregates differs from an older one
+type SnapshotDiff struct {
+	Added   []ACLEDWeeklyAggregate `json:"added"`
+	Removed []ACLEDWeeklyAggregate `json:"removed"`
+	Changed []RowChange            `json:"changed"`
+}
+
+// DiffSnapshots matches rows of two snapshots on their composite key and reports rows only in
+// newSnapshot as added, rows only in oldSnapshot as removed, and rows in both whose event count,
+// fatalities or population exposure differ as changed. Added and changed rows follow the order of
+// newSnapshot and removed rows the order of oldSnapshot. Each snapshot is expected to hold one row
+// per key (see MergeDuplicateKeys); when a key repeats, its last row is used.
+func DiffSnapshots(oldSnapshot, newSnapshot []ACLEDWeeklyAggregate) SnapshotDiff {
+	diff := SnapshotDiff{
+		Added:   make([]ACLEDWeeklyAggregate, 0),
+		Removed: make([]ACLEDWeeklyAggregate, 0),
+		Changed: make([]RowChange, 0),
+	}
+
+	oldRows := make(map[AggregateKey]ACLEDWeeklyAggregate, len(oldSnapshot))
+	for _, row := range oldSnapshot {
+		oldRows[row.Key()] = row
+	}
+	newRows := make(map[AggregateKey]ACLEDWeeklyAggregate, len(newSnapshot))
+	for _, row := range newSnapshot {
+		newRows[row.Key()] = row
+	}
+
+	seen := make(map[AggregateKey]bool, len(newSnapshot))
+	for _, row := range newSnapshot {
+		key := row.Key()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		after := newRows[key]
+		before, ok := oldRows[key]
+		if !ok {
+			diff.Added = append(diff.Added, after)
+			continue
+		}
+		if before.EventCount != after.EventCount || before.Fatalities != after.Fatalities ||
+			before.PopulationExposure != after.PopulationExposure {
+			diff.Changed = append(diff.Changed, RowChange{Key: key, Before: before, After: after})
+		}
+	}
+
+	removed := make(map[AggregateKey]bool, len(oldSnapshot))
+	for _, row := range oldSnapshot {
+		key := row.Key()
+		if _, ok := newRows[key]; ok || removed[key] {
+			continue
+		}
+		removed[key] = true
+		diff.Removed = append(diff.Removed, oldRows[key])
+	}
+	return diff
+}
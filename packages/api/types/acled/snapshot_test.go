@@ -0,0 +1,45 @@
+package acled
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	week := date(2024, time.March, 2)
+	oldSnapshot := []ACLEDWeeklyAggregate{
+		weekRow(10, week, 3),
+		weekRow(20, week, 1),
+		weekRow(30, week, 2),
+	}
+	revised := weekRow(10, week, 5)
+	newSnapshot := []ACLEDWeeklyAggregate{
+		weekRow(40, week, 1),
+		revised,
+		// Unchanged
+		weekRow(30, week, 2),
+	}
+
+	diff := DiffSnapshots(oldSnapshot, newSnapshot)
+	if len(diff.Added) != 1 || *diff.Added[0].Admin1ID != 40 {
+		t.Errorf("Added = %+v, want area 40", diff.Added)
+	}
+	if len(diff.Removed) != 1 || *diff.Removed[0].Admin1ID != 20 {
+		t.Errorf("Removed = %+v, want area 20", diff.Removed)
+	}
+	if len(diff.Changed) != 1 {
+		t.Fatalf("Changed = %+v, want area 10", diff.Changed)
+	}
+	change := diff.Changed[0]
+	if change.Key != revised.Key() || change.Before.EventCount != 3 || change.After.EventCount != 5 {
+		t.Errorf("change = %+v, want area 10 going from 3 to 5 events", change)
+	}
+}
+
+func TestDiffSnapshotsIdentical(t *testing.T) {
+	rows := []ACLEDWeeklyAggregate{weekRow(10, date(2024, time.March, 2), 3)}
+	diff := DiffSnapshots(rows, rows)
+	if len(diff.Added) != 0 || len(diff.Removed) != 0 || len(diff.Changed) != 0 {
+		t.Errorf("diff of identical snapshots = %+v, want empty", diff)
+	}
+}
@@ -0,0 +1,53 @@
+package acled
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SparklinePath returns an SVG path drawing the points' values across a width x height box, with the
+// largest value at the top and the smallest at the bottom. Points are spaced evenly in the order
+// given. An empty or single-point series, or one where every value is equal, draws a flat line
+// along the bottom.
+func SparklinePath(points []WeeklyPoint, width, height float64) string {
+	if len(points) < 2 {
+		return fmt.Sprintf("M0 %s L%s %s", formatCoord(height), formatCoord(width), formatCoord(height))
+	}
+
+	minValue, maxValue := points[0].Value, points[0].Value
+	for _, point := range points {
+		minValue = min(minValue, point.Value)
+		maxValue = max(maxValue, point.Value)
+	}
+
+	var b strings.Builder
+	step := width / float64(len(points)-1)
+	for i, point := range points {
+		y := height
+		if maxValue > minValue {
+			y = height - (point.Value-minValue)/(maxValue-minValue)*height
+		}
+		command := "L"
+		if i == 0 {
+			command = "M"
+		} else {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%s%s %s", command, formatCoord(float64(i)*step), formatCoord(y))
+	}
+	return b.String()
+}
+
+// SparklineSVG returns a minimal standalone SVG document rendering the points as a sparkline
+func SparklineSVG(points []WeeklyPoint, width, height int) string {
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+
+		`<path d="%s" fill="none" stroke="currentColor" stroke-width="1"/></svg>`,
+		width, height, width, height, SparklinePath(points, float64(width), float64(height)))
+}
+
+// formatCoord formats an SVG coordinate with at most two decimals to keep paths short
+func formatCoord(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,35 @@
+package acled
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSparklinePath(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		points []WeeklyPoint
+		want   string
+	}{
+		{"scaled to the box", consecutiveWeeks(0, 10, 5), "M0 20 L50 0 L100 10"},
+		{"rounded coordinates", consecutiveWeeks(0, 1, 2, 3), "M0 20 L33.33 13.33 L66.67 6.67 L100 0"},
+		{"flat series", consecutiveWeeks(4, 4), "M0 20 L100 20"},
+		{"single point", consecutiveWeeks(7), "M0 20 L100 20"},
+		{"empty", nil, "M0 20 L100 20"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SparklinePath(tt.points, 100, 20); got != tt.want {
+				t.Errorf("SparklinePath = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSparklineSVG(t *testing.T) {
+	svg := SparklineSVG(consecutiveWeeks(0, 10, 5), 100, 20)
+	for _, want := range []string{`width="100" height="20"`, `viewBox="0 0 100 20"`, `d="M0 20 L50 0 L100 10"`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SparklineSVG = %s, missing %s", svg, want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package acled
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// disorderTypeForSubEventType resolves the disorder type of Riots, which depends on the sub-event
+func disorderTypeForSubEventType(eventType EventType, subEventType SubEventType) (DisorderType, bool) {
+	if eventType != EventTypeRiots {
+		return DisorderTypeForEventType(eventType)
+	}
+	switch subEventType {
+	case SubEventTypeRiotsViolentDemonstration:
+		return DisorderTypeDemonstrations, true
+	case SubEventTypeRiotsMobViolence:
+		return DisorderTypePoliticalViolence, true
+	}
+	return "", false
+}
+
+// Validate checks that the aggregate is internally consistent before it is persisted, returning
+// every violation joined into a single error
+func (a ACLEDWeeklyAggregate) Validate() error {
+	var errs []error
+
+	if !a.Week.Equal(NormalizeWeek(a.Week)) {
+		errs = append(errs, fmt.Errorf("week %s is not midnight UTC of the Saturday starting an ACLED week", a.Week.Format(time.RFC3339)))
+	}
+
+	eventType, ok := EventTypeForSubEventType(a.SubEventType)
+	switch {
+	case !ok:
+		errs = append(errs, fmt.Errorf("unknown sub-event type %q", a.SubEventType))
+	case eventType != a.EventType:
+		errs = append(errs, fmt.Errorf("sub-event type %q belongs to %q, not %q", a.SubEventType, eventType, a.EventType))
+	}
+
+	if disorderType, ok := disorderTypeForSubEventType(a.EventType, a.SubEventType); !ok {
+		errs = append(errs, fmt.Errorf("cannot determine the disorder type for event type %q", a.EventType))
+	} else if disorderType != a.DisorderType {
+		errs = append(errs, fmt.Errorf("disorder type %q does not match %q expected for %q", a.DisorderType, disorderType, a.SubEventType))
+	}
+
+	if a.CentroidLongitude < -180 || a.CentroidLongitude > 180 {
+		errs = append(errs, fmt.Errorf("centroid longitude %v is outside [-180, 180]", a.CentroidLongitude))
+	}
+	if a.CentroidLatitude < -90 || a.CentroidLatitude > 90 {
+		errs = append(errs, fmt.Errorf("centroid latitude %v is outside [-90, 90]", a.CentroidLatitude))
+	}
+
+	if a.RegionID == 0 {
+		errs = append(errs, errors.New("region ID is not set"))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+package acled
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// validAggregate returns a consistent Armed clash row
+func validAggregate() ACLEDWeeklyAggregate {
+	return ACLEDWeeklyAggregate{
+		Week:              date(2024, time.March, 2),
+		RegionID:          1,
+		DisorderType:      DisorderTypePoliticalViolence,
+		EventType:         EventTypeBattles,
+		SubEventType:      SubEventTypeBattlesArmedClash,
+		EventCount:        3,
+		CentroidLongitude: 36.8,
+		CentroidLatitude:  -1.3,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := validAggregate().Validate(); err != nil {
+		t.Errorf("valid aggregate failed validation: %v", err)
+	}
+
+	riot := validAggregate()
+	riot.EventType = EventTypeRiots
+	riot.SubEventType = SubEventTypeRiotsViolentDemonstration
+	riot.DisorderType = DisorderTypeDemonstrations
+	if err := riot.Validate(); err != nil {
+		t.Errorf("violent demonstration failed validation: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		modify func(*ACLEDWeeklyAggregate)
+		want   string
+	}{
+		{"week not a Saturday", func(a *ACLEDWeeklyAggregate) { a.Week = date(2024, time.March, 4) }, "not midnight UTC"},
+		{"week not midnight", func(a *ACLEDWeeklyAggregate) { a.Week = a.Week.Add(time.Hour) }, "not midnight UTC"},
+		{"unknown sub-event", func(a *ACLEDWeeklyAggregate) { a.SubEventType = "Duel" }, "unknown sub-event type"},
+		{"sub-event of another event type", func(a *ACLEDWeeklyAggregate) { a.SubEventType = SubEventTypeExplosionsGrenade }, "belongs to"},
+		{"wrong disorder type", func(a *ACLEDWeeklyAggregate) { a.DisorderType = DisorderTypeDemonstrations }, "does not match"},
+		{"riot disorder type", func(a *ACLEDWeeklyAggregate) {
+			a.EventType = EventTypeRiots
+			a.SubEventType = SubEventTypeRiotsMobViolence
+			a.DisorderType = DisorderTypeDemonstrations
+		}, "does not match"},
+		{"longitude out of range", func(a *ACLEDWeeklyAggregate) { a.CentroidLongitude = 181 }, "longitude"},
+		{"latitude out of range", func(a *ACLEDWeeklyAggregate) { a.CentroidLatitude = -90.5 }, "latitude"},
+		{"no region", func(a *ACLEDWeeklyAggregate) { a.RegionID = 0 }, "region ID"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			a := validAggregate()
+			tt.modify(&a)
+			err := a.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryViolation(t *testing.T) {
+	a := validAggregate()
+	a.RegionID = 0
+	a.CentroidLatitude = 100
+	a.Week = a.Week.AddDate(0, 0, 1)
+	err := a.Validate()
+	if err == nil {
+		t.Fatal("Validate() accepted an invalid aggregate")
+	}
+	if lines := strings.Split(err.Error(), "\n"); len(lines) != 3 {
+		t.Errorf("Validate() reported %d violations, want 3: %v", len(lines), err)
+	}
+}
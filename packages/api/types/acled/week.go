@@ -0,0 +1,130 @@
+package acled
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NormalizeWeek returns midnight UTC of the Saturday starting the ACLED week (Saturday to Friday) that contains t
+func NormalizeWeek(t time.Time) time.Time {
+	return NormalizeWeekStart(t, time.Saturday)
+}
+
+// NormalizeWeekStart returns midnight UTC of the most recent weekStart day on or before t, for
+// consumers that bucket weeks from a day other than ACLED's Saturday (e.g. Monday)
+func NormalizeWeekStart(t time.Time, weekStart time.Weekday) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// WeekBucketSQL returns a Postgres expression bucketing a timestamp column into weeks starting on
+// weekStart, matching NormalizeWeekStart. Postgres' date_trunc('week', ...) always truncates to
+// Monday, so the timestamp is shifted forward by the days from weekStart to the following Monday,
+// truncated, and shifted back. For Saturday the shift is 2 days: a Saturday moves to Monday and
+// truncates to itself, while a Friday moves to Sunday and truncates to the Monday before, which
+// shifts back to the previous Saturday.
+func WeekBucketSQL(column string, weekStart time.Weekday) string {
+	shift := (int(time.Monday) - int(weekStart) + 7) % 7
+	if shift == 0 {
+		return fmt.Sprintf("date_trunc('week', %s)", column)
+	}
+	return fmt.Sprintf("(date_trunc('week', %s + interval '%d days') - interval '%d days')", column, shift, shift)
+}
+
+// ChunkWeekRange splits the range [from, to) into consecutive [start, end) windows of
+// weeksPerChunk ACLED weeks each, so large ranges can be queried in batches.
+// Boundaries are aligned to ACLED weeks: from is moved back to the start of its week
+// and to is moved forward to the start of the next week unless it already falls on one.
+// The final window is shorter when the range is not an exact multiple of weeksPerChunk.
+func ChunkWeekRange(from, to time.Time, weeksPerChunk int) [][2]time.Time {
+	if weeksPerChunk < 1 {
+		return nil
+	}
+
+	// The range is half-open, so its last week is the one containing the instant before to
+	chunks := make([][2]time.Time, 0)
+	weeks := 0
+	it := NewWeekIterator(from, to.Add(-time.Nanosecond))
+	for week, ok := it.Next(); ok; week, ok = it.Next() {
+		if weeks%weeksPerChunk == 0 {
+			chunks = append(chunks, [2]time.Time{week, week})
+		}
+		chunks[len(chunks)-1][1] = week.AddDate(0, 0, 7)
+		weeks++
+	}
+	return chunks
+}
+
+// ISOWeekLabel returns the ISO 8601 year-week label for t, e.g. "2024-W09". Around the new year
+// the ISO year can differ from the calendar year: 2024-12-30 is in 2025-W01 and 2021-01-01 is in 2020-W53.
+func ISOWeekLabel(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+// yearWeekPattern matches a year and ISO week number, e.g. "2024-09" or "2024-W09"
+var yearWeekPattern = regexp.MustCompile(`^(\d{4})-W?(\d{1,2})$`)
+
+// weekDateLayouts are the date formats ParseWeek accepts besides year-week strings
+var weekDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"2006/01/02",
+	"02 January 2006",
+	"2 January 2006",
+	"January 2, 2006",
+}
+
+// ParseWeek parses a date in the formats found in ACLED exports and returns the start of the ACLED
+// week containing it, via NormalizeWeek. Accepted formats are RFC3339 timestamps, YYYY-MM-DD
+// (optionally with a time or slashes), ACLED's "15 January 2024", "January 15, 2024", and a year and
+// ISO week as YYYY-WW or YYYY-Www. A year-week resolves to the ACLED week containing that ISO
+// week's Monday, i.e. the Saturday before it.
+func ParseWeek(raw string) (time.Time, error) {
+	value := strings.TrimSpace(raw)
+
+	if match := yearWeekPattern.FindStringSubmatch(value); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		week, _ := strconv.Atoi(match[2])
+		// January 4th is always in ISO week 1
+		jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+		monday := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+7*(week-1))
+		if isoYear, isoWeek := monday.ISOWeek(); week < 1 || isoYear != year || isoWeek != week {
+			return time.Time{}, fmt.Errorf("invalid week %q: %d has no ISO week %d", raw, year, week)
+		}
+		return NormalizeWeek(monday), nil
+	}
+
+	for _, layout := range weekDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return NormalizeWeek(t), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date format %q", raw)
+}
+
+// LatestCompleteWeek returns the start of the most recent ACLED week that has fully ended by the
+// clock's current time, i.e. the week before the one in progress
+func LatestCompleteWeek(clock Clock) time.Time {
+	return NormalizeWeek(clock.Now()).AddDate(0, 0, -7)
+}
+
+// ExcludePartialWeeks returns the rows whose week has fully ended by the clock's current time,
+// dropping the week in progress (and any later ones), whose totals are still incomplete
+func ExcludePartialWeeks(rows []ACLEDWeeklyAggregate, clock Clock) []ACLEDWeeklyAggregate {
+	current := NormalizeWeek(clock.Now())
+	complete := make([]ACLEDWeeklyAggregate, 0, len(rows))
+	for _, row := range rows {
+		if NormalizeWeek(row.Week).Before(current) {
+			complete = append(complete, row)
+		}
+	}
+	return complete
+}
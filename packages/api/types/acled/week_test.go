@@ -0,0 +1,177 @@
+package acled
+
+import (
+	"testing"
+	"time"
+)
+
+// date returns midnight UTC of the given day
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestNormalizeWeek(t *testing.T) {
+	saturday := date(2024, time.March, 9)
+	for _, tt := range []time.Time{
+		saturday,
+		saturday.Add(23 * time.Hour),
+		date(2024, time.March, 12),
+		time.Date(2024, time.March, 15, 23, 59, 59, 0, time.UTC),
+		// Friday evening in UTC-5 is already Saturday in UTC
+		time.Date(2024, time.March, 8, 20, 0, 0, 0, time.FixedZone("EST", -5*3600)),
+	} {
+		if got := NormalizeWeek(tt); !got.Equal(saturday) {
+			t.Errorf("NormalizeWeek(%s) = %s, want %s", tt, got, saturday)
+		}
+	}
+	if got, want := NormalizeWeek(date(2024, time.March, 8)), date(2024, time.March, 2); !got.Equal(want) {
+		t.Errorf("NormalizeWeek(Friday) = %s, want the previous Saturday %s", got, want)
+	}
+}
+
+func TestNormalizeWeekStart(t *testing.T) {
+	wednesday := time.Date(2024, time.March, 13, 18, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		weekStart time.Weekday
+		want      time.Time
+	}{
+		{time.Saturday, date(2024, time.March, 9)},
+		{time.Sunday, date(2024, time.March, 10)},
+		{time.Monday, date(2024, time.March, 11)},
+		{time.Wednesday, date(2024, time.March, 13)},
+		{time.Thursday, date(2024, time.March, 7)},
+	} {
+		if got := NormalizeWeekStart(wednesday, tt.weekStart); !got.Equal(tt.want) {
+			t.Errorf("NormalizeWeekStart(%s) = %s, want %s", tt.weekStart, got, tt.want)
+		}
+	}
+}
+
+func TestWeekBucketSQL(t *testing.T) {
+	for _, tt := range []struct {
+		weekStart time.Weekday
+		want      string
+	}{
+		{time.Monday, "date_trunc('week', event_date)"},
+		{time.Saturday, "(date_trunc('week', event_date + interval '2 days') - interval '2 days')"},
+		{time.Sunday, "(date_trunc('week', event_date + interval '1 days') - interval '1 days')"},
+		{time.Tuesday, "(date_trunc('week', event_date + interval '6 days') - interval '6 days')"},
+	} {
+		if got := WeekBucketSQL("event_date", tt.weekStart); got != tt.want {
+			t.Errorf("WeekBucketSQL(%s) = %s, want %s", tt.weekStart, got, tt.want)
+		}
+	}
+}
+
+func TestChunkWeekRange(t *testing.T) {
+	// Tuesday 2024-01-02 to Wednesday 2024-01-31 spans the five ACLED weeks from 2023-12-30
+	chunks := ChunkWeekRange(date(2024, time.January, 2), date(2024, time.January, 31), 2)
+	want := [][2]time.Time{
+		{date(2023, time.December, 30), date(2024, time.January, 13)},
+		{date(2024, time.January, 13), date(2024, time.January, 27)},
+		{date(2024, time.January, 27), date(2024, time.February, 3)},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("ChunkWeekRange returned %d chunks, want %d: %v", len(chunks), len(want), chunks)
+	}
+	for i := range want {
+		if !chunks[i][0].Equal(want[i][0]) || !chunks[i][1].Equal(want[i][1]) {
+			t.Errorf("chunk %d = %v, want %v", i, chunks[i], want[i])
+		}
+	}
+
+	// An end already on a week boundary is exclusive
+	chunks = ChunkWeekRange(date(2024, time.January, 6), date(2024, time.January, 20), 4)
+	if len(chunks) != 1 || !chunks[0][1].Equal(date(2024, time.January, 20)) {
+		t.Errorf("ChunkWeekRange on week boundaries = %v, want one chunk ending 2024-01-20", chunks)
+	}
+
+	if chunks := ChunkWeekRange(date(2024, time.January, 6), date(2024, time.January, 6), 1); len(chunks) != 0 {
+		t.Errorf("ChunkWeekRange of an empty range = %v, want none", chunks)
+	}
+	if chunks := ChunkWeekRange(date(2024, time.January, 6), date(2024, time.January, 20), 0); chunks != nil {
+		t.Errorf("ChunkWeekRange with 0 weeks per chunk = %v, want nil", chunks)
+	}
+}
+
+func TestISOWeekLabel(t *testing.T) {
+	for _, tt := range []struct {
+		t    time.Time
+		want string
+	}{
+		{date(2024, time.March, 2), "2024-W09"},
+		// The ACLED week starting 2024-12-28 straddles the new year into 2025-W01
+		{date(2024, time.December, 28), "2024-W52"},
+		{date(2024, time.December, 30), "2025-W01"},
+		{date(2021, time.January, 1), "2020-W53"},
+		{date(2026, time.January, 3), "2026-W01"},
+	} {
+		if got := ISOWeekLabel(tt.t); got != tt.want {
+			t.Errorf("ISOWeekLabel(%s) = %s, want %s", tt.t.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestExcludePartialWeeks(t *testing.T) {
+	// Wednesday 2024-03-13 falls in the ACLED week starting Saturday 2024-03-09
+	clock := FixedClock{Time: time.Date(2024, time.March, 13, 15, 0, 0, 0, time.UTC)}
+
+	if got, want := LatestCompleteWeek(clock), date(2024, time.March, 2); !got.Equal(want) {
+		t.Errorf("LatestCompleteWeek = %s, want %s", got, want)
+	}
+
+	rows := []ACLEDWeeklyAggregate{
+		{Week: date(2024, time.February, 24), EventCount: 1},
+		{Week: date(2024, time.March, 2), EventCount: 2},
+		{Week: date(2024, time.March, 9), EventCount: 3},
+	}
+	complete := ExcludePartialWeeks(rows, clock)
+	if len(complete) != 2 || complete[0].EventCount != 1 || complete[1].EventCount != 2 {
+		t.Errorf("ExcludePartialWeeks = %+v, want the first two rows", complete)
+	}
+
+	// Once the week ends it is kept
+	clock.Time = date(2024, time.March, 16)
+	if complete := ExcludePartialWeeks(rows, clock); len(complete) != 3 {
+		t.Errorf("ExcludePartialWeeks after the week ended kept %d rows, want 3", len(complete))
+	}
+}
+
+func TestParseWeek(t *testing.T) {
+	saturday := date(2024, time.January, 13)
+	for _, raw := range []string{
+		"2024-01-15",
+		" 2024-01-13 ",
+		"2024-01-19T23:59:59Z",
+		"2024-01-15T08:00:00",
+		"2024-01-15 08:00:00",
+		"2024/01/15",
+		"15 January 2024",
+		"January 15, 2024",
+		"2024-W03",
+		"2024-03",
+	} {
+		got, err := ParseWeek(raw)
+		if err != nil {
+			t.Errorf("ParseWeek(%q): %v", raw, err)
+			continue
+		}
+		if !got.Equal(saturday) {
+			t.Errorf("ParseWeek(%q) = %s, want %s", raw, got, saturday)
+		}
+	}
+
+	// The first ISO week of 2024 starts on Monday January 1st, in the ACLED week of December 30th
+	if got, err := ParseWeek("2024-W01"); err != nil || !got.Equal(date(2023, time.December, 30)) {
+		t.Errorf("ParseWeek(2024-W01) = %s, %v, want 2023-12-30", got, err)
+	}
+	if _, err := ParseWeek("2020-W53"); err != nil {
+		t.Errorf("2020 has 53 ISO weeks: %v", err)
+	}
+
+	for _, raw := range []string{"2021-W53", "2024-W00", "15/01/2024", "last week", ""} {
+		if _, err := ParseWeek(raw); err == nil {
+			t.Errorf("ParseWeek(%q) succeeded, want an error", raw)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package acled
+
+import "time"
+
+// WeekIterator steps through the ACLED weeks of an inclusive range. It yields the Saturday starting
+// each week, from the week containing from to the week containing to, and yields nothing when from
+// is after to.
+type WeekIterator struct {
+	first   time.Time
+	last    time.Time
+	next    time.Time
+	isEmpty bool
+}
+
+// NewWeekIterator returns an iterator over the ACLED weeks from the week containing from to the
+// week containing to, inclusive
+func NewWeekIterator(from, to time.Time) *WeekIterator {
+	it := &WeekIterator{
+		first:   NormalizeWeek(from),
+		last:    NormalizeWeek(to),
+		isEmpty: from.After(to),
+	}
+	it.Reset()
+	return it
+}
+
+// Next returns the next week start. ok is false once the range is exhausted.
+func (it *WeekIterator) Next() (week time.Time, ok bool) {
+	if it.isEmpty || it.next.After(it.last) {
+		return time.Time{}, false
+	}
+	week = it.next
+	it.next = it.next.AddDate(0, 0, 7)
+	return week, true
+}
+
+// Reset restarts the iterator from the first week of the range
+func (it *WeekIterator) Reset() {
+	it.next = it.first
+}
@@ -0,0 +1,43 @@
+package acled
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekIterator(t *testing.T) {
+	collect := func(it *WeekIterator) []time.Time {
+		weeks := make([]time.Time, 0)
+		for week, ok := it.Next(); ok; week, ok = it.Next() {
+			weeks = append(weeks, week)
+		}
+		return weeks
+	}
+
+	it := NewWeekIterator(date(2024, time.March, 5), date(2024, time.March, 27))
+	weeks := collect(it)
+	want := []time.Time{date(2024, time.March, 2), date(2024, time.March, 9), date(2024, time.March, 16), date(2024, time.March, 23)}
+	if len(weeks) != len(want) {
+		t.Fatalf("iterator yielded %v, want %v", weeks, want)
+	}
+	for i := range want {
+		if !weeks[i].Equal(want[i]) {
+			t.Errorf("week %d = %s, want %s", i, weeks[i], want[i])
+		}
+	}
+	if _, ok := it.Next(); ok {
+		t.Error("exhausted iterator yielded another week")
+	}
+
+	it.Reset()
+	if again := collect(it); len(again) != len(want) {
+		t.Errorf("after Reset the iterator yielded %d weeks, want %d", len(again), len(want))
+	}
+
+	if weeks := collect(NewWeekIterator(date(2024, time.March, 9), date(2024, time.March, 9))); len(weeks) != 1 {
+		t.Errorf("single-day range yielded %v, want one week", weeks)
+	}
+	if weeks := collect(NewWeekIterator(date(2024, time.March, 10), date(2024, time.March, 9))); len(weeks) != 0 {
+		t.Errorf("reversed range yielded %v, want none", weeks)
+	}
+}
@@ -0,0 +1,38 @@
+import { describe, expect, test } from 'bun:test';
+import { buildChangeReport, formatChangeReport } from './change-report';
+
+describe('buildChangeReport', () => {
+  test('counts inserted and updated rows and the fatalities delta', () => {
+    const report = buildChangeReport('Eastern Africa', [
+      { inserted: true, week: new Date('2024-03-09T00:00:00Z'), fatalities: 4 },
+      { inserted: false, week: new Date('2024-03-02T00:00:00Z'), fatalities: 10 },
+      { inserted: false, week: '2024-03-02T00:00:00Z', fatalities: 3 },
+      { inserted: true, week: new Date('2024-03-09T00:00:00Z'), fatalities: null },
+    ], 9);
+
+    expect(report).toEqual({
+      source: 'Eastern Africa',
+      weeks: ['2024-03-02', '2024-03-09'],
+      inserted: 2,
+      updated: 2,
+      // 17 fatalities now against 9 in the overwritten rows
+      fatalitiesDelta: 8,
+    });
+    expect(formatChangeReport(report)).toBe('2 inserted, 2 updated across 2 weeks, fatalities +8');
+  });
+
+  test('reports a negative delta when revisions lower fatalities', () => {
+    const report = buildChangeReport('Middle East', [
+      { inserted: false, week: new Date('2024-03-02T00:00:00Z'), fatalities: 5 },
+    ], 12);
+
+    expect(report.fatalitiesDelta).toBe(-7);
+    expect(formatChangeReport(report)).toBe('0 inserted, 1 updated across 1 weeks, fatalities -7');
+  });
+
+  test('reports nothing for an empty upsert', () => {
+    expect(buildChangeReport('Europe', [], 0)).toEqual({
+      source: 'Europe', weeks: [], inserted: 0, updated: 0, fatalitiesDelta: 0,
+    });
+  });
+});
@@ -0,0 +1,45 @@
+// Summary of what an upsert changed in acled_weekly_agg
+export type ChangeReport = {
+  source: string;
+  weeks: string[];
+  inserted: number;
+  updated: number;
+  fatalitiesDelta: number;
+};
+
+// A row returned by the acled_weekly_agg upsert. `inserted` is true for new rows and false for rows
+// updated by ON CONFLICT.
+export type UpsertedRow = {
+  inserted: boolean;
+  week: Date | string;
+  fatalities: number | null;
+};
+
+// Build the change report for an upsert from the rows it returned and the summed fatalities of the
+// rows it overwrote. Weeks are distinct YYYY-MM-DD dates, sorted.
+export function buildChangeReport(source: string, upserted: UpsertedRow[], priorFatalities: number): ChangeReport {
+  const weeks = new Set<string>();
+  let inserted = 0;
+  let fatalities = 0;
+  for (const row of upserted) {
+    if (row.inserted) {
+      inserted++;
+    }
+    weeks.add(new Date(row.week).toISOString().slice(0, 10));
+    fatalities += row.fatalities ?? 0;
+  }
+
+  return {
+    source,
+    weeks: [...weeks].sort(),
+    inserted,
+    updated: upserted.length - inserted,
+    fatalitiesDelta: fatalities - priorFatalities,
+  };
+}
+
+// Format a change report as a one-line summary for the logs
+export function formatChangeReport(report: ChangeReport): string {
+  return `${report.inserted} inserted, ${report.updated} updated across ${report.weeks.length} weeks, ` +
+    `fatalities ${report.fatalitiesDelta >= 0 ? '+' : ''}${report.fatalitiesDelta}`;
+}
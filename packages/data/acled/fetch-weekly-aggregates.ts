@@ -31,6 +31,7 @@ import * as XLSX from 'xlsx';
 import { readFileSync } from 'fs';
 import { join } from 'path';
 import { DisorderType, EventType, SubEventType, GeographicAreaType, GeographicArea } from '@crushingviz/types'
+import { buildChangeReport, formatChangeReport, type ChangeReport, type UpsertedRow } from './change-report';
 
 // Environment variables
 const ACLED_EMAIL = process.env.ACLED_EMAIL!;
@@ -127,10 +128,11 @@ async function hasFilenameChanged(region: string, filename: string): Promise<boo
 }
 
 // Process a single region's XLSX file
-async function processRegionFile(region: string, filePath: string, filename: string): Promise<void> {
+async function processRegionFile(region: string, filePath: string, filename: string): Promise<ChangeReport | undefined> {
   const startTime = Date.now();
   let status = 'successful';
   let errorMessage: string | undefined;
+  let report: ChangeReport | undefined;
 
   try {
     console.log(`Processing ${region} from ${filename}...`);
@@ -238,12 +240,30 @@ async function processRegionFile(region: string, filePath: string, filename: str
       }
 
       if (aggregateData.length > 0) {
+        const aggregateJSON = JSON.stringify(aggregateData);
+
+        // Sum the fatalities of the rows about to be overwritten so the net change can be reported
+        const [prior]: { fatalities: number }[] = await tx`
+          SELECT COALESCE(SUM(a.fatalities), 0)::int AS fatalities
+          FROM acled_weekly_agg a
+          JOIN json_to_recordset(${aggregateJSON}::json) AS t(
+            week timestamptz, region_id int, country_id int, admin1_id int,
+            disorder_type text, event_type text, sub_event_type text
+          ) ON a.week = t.week
+            AND a.region_id = t.region_id
+            AND a.country_id = t.country_id
+            AND a.admin1_id = t.admin1_id
+            AND a.disorder_type::text = t.disorder_type
+            AND a.event_type::text = t.event_type
+            AND a.sub_event_type::text = t.sub_event_type
+        `;
+
         console.log(`  Upserting ${aggregateData.length} rows...`);
-        await tx`
+        const upserted: UpsertedRow[] = await tx`
           INSERT INTO acled_weekly_agg (
             week, region_id, country_id, admin1_id, disorder_type, event_type, sub_event_type,
             event_count, fatalities, population_exposure, centroid_longitude, centroid_latitude
-          ) SELECT * FROM json_to_recordset(${JSON.stringify(aggregateData)}::json) AS t(
+          ) SELECT * FROM json_to_recordset(${aggregateJSON}::json) AS t(
             week timestamptz, region_id int, country_id int, admin1_id int,
             disorder_type text, event_type text, sub_event_type text, event_count int, fatalities int,
             population_exposure int, centroid_longitude float8, centroid_latitude float8
@@ -254,7 +274,22 @@ async function processRegionFile(region: string, filePath: string, filename: str
             population_exposure = EXCLUDED.population_exposure,
             centroid_longitude = EXCLUDED.centroid_longitude,
             centroid_latitude = EXCLUDED.centroid_latitude
+          RETURNING (xmax = 0) AS inserted, week, fatalities
+        `;
+
+        // xmax is 0 for freshly inserted rows and set for rows updated by ON CONFLICT
+        report = buildChangeReport(region, upserted, prior?.fatalities ?? 0);
+
+        await tx`
+          INSERT INTO ingestion_log (source, type, weeks, inserted_count, updated_count, fatalities_delta)
+          VALUES (
+            ${report.source}, 'acled_weekly_agg',
+            ARRAY(SELECT json_array_elements_text(${JSON.stringify(report.weeks)}::json)::timestamp),
+            ${report.inserted}, ${report.updated}, ${report.fatalitiesDelta}
+          )
         `;
+
+        console.log(`  Changes: ${formatChangeReport(report)}`);
       }
 
       console.log(`  Successfully processed ${rows.length} rows for ${region}`);
@@ -272,6 +307,8 @@ async function processRegionFile(region: string, filePath: string, filename: str
       VALUES (${region}, ${status}, ${duration}, 'acled_weekly_agg', ${JSON.stringify({ filename, error: errorMessage })}::jsonb)
     `;
   }
+
+  return report;
 }
 
 // Main function
@@ -341,7 +378,8 @@ async function main() {
 
     console.log(`Found ${regionLinks.length} regions:`, regionLinks.map(r => r.region).join(', '));
 
-    // Process each region
+    // Process each region, keeping what each upsert changed for the summary
+    const reports: ChangeReport[] = [];
     for (const { region, url } of regionLinks) {
       try {
         console.log(`\nProcessing region: ${region}`);
@@ -385,13 +423,24 @@ async function main() {
         console.log(`  Downloaded to ${downloadPath}`);
 
         // Process the file
-        await processRegionFile(region, downloadPath, filename);
+        const report = await processRegionFile(region, downloadPath, filename);
+        if (report) {
+          reports.push(report);
+        }
       } catch (error) {
         console.error(`Failed to process region ${region}:`, error);
         // Continue with next region
       }
     }
 
+    console.log('\nChanges by region:');
+    for (const report of reports) {
+      console.log(`  ${report.source}: ${formatChangeReport(report)}`);
+    }
+    if (!reports.length) {
+      console.log('  No regions changed');
+    }
+
     console.log('\nCompleted successfully!');
   } catch (error) {
     console.error('Fatal error:', error);
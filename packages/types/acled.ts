@@ -144,3 +144,401 @@ export interface ACLEDWeeklyAggregateBase {
  * ACLEDWeeklyAggregate is an alias for ACLEDWeeklyAggregateBase
  */
 export type ACLEDWeeklyAggregate = ACLEDWeeklyAggregateBase;
+
+//////////
+// source: breakdown.go
+
+/**
+ * SubEventTotal is the summed activity for one sub-event type
+ */
+export interface SubEventTotal {
+	sub_event_type: SubEventType;
+	event_count: number /* uint64 */;
+	fatalities: number /* uint64 */;
+}
+/**
+ * SubEventGroup is a custom analytical grouping of sub-event types, e.g. to split Explosions/Remote
+ * violence into aerial and ground attacks
+ */
+export interface SubEventGroup {
+	name: string;
+	sub_event_types: SubEventType[];
+}
+/**
+ * SubEventGroupTotal is the summed activity for one SubEventGroup
+ */
+export interface SubEventGroupTotal {
+	group: string;
+	event_count: number /* uint64 */;
+	fatalities: number /* uint64 */;
+}
+
+//////////
+// source: change.go
+
+/**
+ * ChangeRank is an area's week-over-week change in a metric
+ */
+export interface ChangeRank {
+	area_id: number /* int */;
+	previous: number /* float64 */;
+	current: number /* float64 */;
+	/**
+	 * Change is the percent change from the previous week, e.g. 150 when the value went from 2 to 5
+	 */
+	change: number /* float64 */;
+}
+
+//////////
+// source: compare.go
+
+/**
+ * MaxCompareAreas is the most areas CompareAreas lines up at once
+ */
+export const MaxCompareAreas = 10;
+/**
+ * AreaComparison is one area's weekly series on the axis shared by every area in a comparison
+ */
+export interface AreaComparison {
+	area_id: number /* int */;
+	series: WeeklyPoint[];
+	total: number /* float64 */;
+}
+
+//////////
+// source: completeness.go
+
+/**
+ * CompletenessRow compares the areas reporting in an ACLED week with the areas expected to
+ */
+export interface CompletenessRow {
+	week: string /* RFC3339 */;
+	reporting_areas: number /* int */;
+	expected_areas: number /* int */;
+	/**
+	 * Ratio is ReportingAreas / ExpectedAreas, low for weeks that are likely incomplete
+	 */
+	ratio: number /* float64 */;
+}
+
+//////////
+// source: coverage.go
+
+/**
+ * CoverageRow is the span of ACLED weeks an area has data for
+ */
+export interface CoverageRow {
+	area_id: number /* int */;
+	/**
+	 * FirstWeek and LastWeek are the earliest and latest weeks with any events
+	 */
+	first_week: string /* RFC3339 */;
+	last_week: string /* RFC3339 */;
+	/**
+	 * ActiveWeeks is the number of distinct weeks with any events between them
+	 */
+	active_weeks: number /* int */;
+}
+
+//////////
+// source: drilldown.go
+
+/**
+ * AreaMetric is the summed activity of a geographic area and the areas below it
+ */
+export interface AreaMetric {
+	area_id: number /* int */;
+	name: string;
+	type: GeographicAreaType;
+	event_count: number /* uint64 */;
+	fatalities: number /* uint64 */;
+	/**
+	 * PopulationExposure is the largest exposure of any row, since exposure estimates can't be summed
+	 */
+	population_exposure: number /* uint64 */;
+}
+
+//////////
+// source: duration.go
+
+/**
+ * DurationRow is the longest run of consecutive active ACLED weeks for an area
+ */
+export interface DurationRow {
+	area_id: number /* int */;
+	/**
+	 * Weeks is the number of consecutive weeks in the run
+	 */
+	weeks: number /* int */;
+	/**
+	 * Start is the first week of the run
+	 */
+	start: string /* RFC3339 */;
+	/**
+	 * End is the last week of the run
+	 */
+	end: string /* RFC3339 */;
+}
+
+//////////
+// source: event.go
+
+/**
+ * ACLEDEvent is a single raw ACLED event record, before weekly aggregation
+ */
+export interface ACLEDEvent {
+	/**
+	 * EventID is ACLED's identifier for the event (event_id_cnty)
+	 */
+	event_id: string;
+	/**
+	 * EventDate is the day the event took place
+	 */
+	event_date: string /* RFC3339 */;
+	/**
+	 * RegionID is the foreign key referencing the region in the geographic_area table
+	 */
+	region_id: number /* int */;
+	/**
+	 * CountryID is the foreign key referencing the country in the geographic_area table
+	 */
+	country_id?: number /* int */;
+	/**
+	 * Admin1ID is the foreign key referencing the admin1 area in the geographic_area table
+	 */
+	admin1_id?: number /* int */;
+	/**
+	 * Location is the name of the place the event took place
+	 */
+	location: string;
+	/**
+	 * Actor1 and Actor2 are the named actors involved in the event. Actor2 may be empty.
+	 */
+	actor1: string;
+	actor2: string;
+	disorder_type: DisorderType;
+	event_type: EventType;
+	sub_event_type: SubEventType;
+	/**
+	 * Fatalities is the reported number of fatalities for the event
+	 */
+	fatalities: number /* uint64 */;
+	longitude: number /* float64 */;
+	latitude: number /* float64 */;
+}
+
+//////////
+// source: geo.go
+
+/**
+ * DefaultCentroidDecimals is the centroid precision used for API payloads, roughly 1m at the equator
+ */
+export const DefaultCentroidDecimals = 5;
+
+//////////
+// source: grid.go
+
+/**
+ * GridCell is a square lat/lon cell of a density grid with the summed metric of the rows inside it
+ */
+export interface GridCell {
+	/**
+	 * X and Y index the cell as floor(lon / cellDegrees) and floor(lat / cellDegrees)
+	 */
+	x: number /* int */;
+	y: number /* int */;
+	/**
+	 * Longitude and Latitude are the center of the cell
+	 */
+	longitude: number /* float64 */;
+	latitude: number /* float64 */;
+	total: number /* float64 */;
+}
+
+//////////
+// source: interval.go
+
+/**
+ * Z95 is the standard normal quantile for a 95% interval
+ */
+export const Z95 = 1.96;
+/**
+ * RateInterval is a point estimate with a rough uncertainty band, for rendering error bars. The
+ * bands are statistical heuristics computed here, not values provided by ACLED.
+ */
+export interface RateInterval {
+	value: number /* float64 */;
+	lower: number /* float64 */;
+	upper: number /* float64 */;
+}
+
+//////////
+// source: key.go
+
+/**
+ * AggregateKey is the composite key identifying an acled_weekly_agg row. Nil area IDs are
+ * represented as 0 and the week is held in UTC so keys can be compared and used in maps.
+ */
+export interface AggregateKey {
+	week: string /* RFC3339 */;
+	region_id: number /* int */;
+	country_id: number /* int */;
+	admin1_id: number /* int */;
+	disorder_type: DisorderType;
+	event_type: EventType;
+	sub_event_type: SubEventType;
+}
+
+//////////
+// source: metric.go
+
+/**
+ * Metric is a numeric field of an ACLEDWeeklyAggregate that can be summarized or mapped
+ */
+export const MetricEventCount = "event_count";
+export const MetricFatalities = "fatalities";
+export const MetricPopulationExposure = "population_exposure";
+export type Metric = typeof MetricEventCount | typeof MetricFatalities | typeof MetricPopulationExposure;
+
+//////////
+// source: near.go
+
+/**
+ * NearbyAggregate is an aggregate within a search radius, with the distance to its centroid
+ */
+export interface NearbyAggregate {
+	aggregate: ACLEDWeeklyAggregate;
+	distance_km: number /* float64 */;
+}
+
+//////////
+// source: pivot.go
+
+/**
+ * PivotDimension is a field of an ACLEDWeeklyAggregate that a pivot table can lay out as rows or columns
+ */
+export const PivotDimensionArea = "area";
+export const PivotDimensionWeek = "week";
+export const PivotDimensionDisorderType = "disorder_type";
+export const PivotDimensionEventType = "event_type";
+export const PivotDimensionSubEventType = "sub_event_type";
+export type PivotDimension = typeof PivotDimensionArea | typeof PivotDimensionWeek | typeof PivotDimensionDisorderType | typeof PivotDimensionEventType | typeof PivotDimensionSubEventType;
+/**
+ * PivotTable is a metric summed over one dimension of the aggregates down the rows and another
+ * across the columns, with row and column totals, for spreadsheet-style views
+ */
+export interface PivotTable {
+	rows: string[];
+	columns: string[];
+	/**
+	 * Cells holds one slice per row with one value per column, 0 where no aggregate matched both
+	 */
+	cells: number /* float64 */[][];
+	row_totals: number /* float64 */[];
+	column_totals: number /* float64 */[];
+	total: number /* float64 */;
+}
+
+//////////
+// source: qa.go
+
+/**
+ * QAFlag marks an aggregate whose values look like a data-entry error
+ */
+export interface QAFlag {
+	key: AggregateKey;
+	/**
+	 * FatalitiesPerEvent is the ratio that exceeded the threshold
+	 */
+	fatalities_per_event: number /* float64 */;
+}
+
+//////////
+// source: region.go
+
+/**
+ * RegionTotal is the summed activity of every area within an ACLED region
+ */
+export interface RegionTotal {
+	region_id: number /* int */;
+	name: string;
+	event_count: number /* uint64 */;
+	fatalities: number /* uint64 */;
+	/**
+	 * PopulationExposure is the largest exposure of any row, since exposure estimates can't be summed
+	 */
+	population_exposure: number /* uint64 */;
+}
+
+//////////
+// source: scale.go
+
+/**
+ * ScaleMinMax maps values linearly onto [0, 1] between the smallest and largest value
+ */
+export const ScaleMinMax = "min-max";
+/**
+ * ScaleZScore expresses values as standard deviations from the mean
+ */
+export const ScaleZScore = "z-score";
+/**
+ * ScaleMethod is how metric values are normalized for a choropleth color scale
+ */
+export type ScaleMethod = typeof ScaleMinMax | typeof ScaleZScore;
+
+//////////
+// source: series.go
+
+/**
+ * WeeklyPoint is the value of a series for one ACLED week
+ */
+export interface WeeklyPoint {
+	week: string /* RFC3339 */;
+	value: number /* float64 */;
+}
+/**
+ * TrendLabel classifies a week's value against the weeks before it
+ */
+export const TrendEscalating = "escalating";
+export const TrendDeEscalating = "de-escalating";
+export const TrendStable = "stable";
+/**
+ * TrendInsufficientData marks weeks without lookback weeks of history before them
+ */
+export const TrendInsufficientData = "insufficient_data";
+export type TrendLabel = typeof TrendEscalating | typeof TrendDeEscalating | typeof TrendStable | typeof TrendInsufficientData;
+
+//////////
+// source: share.go
+
+/**
+ * SharePoint is an event type's share of the events of one ACLED week
+ */
+export interface SharePoint {
+	week: string /* RFC3339 */;
+	/**
+	 * Share is the percentage (0-100) of the week's events that were of this event type
+	 */
+	share: number /* float64 */;
+	event_count: number /* uint64 */;
+}
+
+//////////
+// source: snapshot.go
+
+/**
+ * RowChange is a row present in both snapshots whose metrics differ
+ */
+export interface RowChange {
+	key: AggregateKey;
+	before: ACLEDWeeklyAggregate;
+	after: ACLEDWeeklyAggregate;
+}
+/**
+ * SnapshotDiff describes how a newer download of ACLED aggregates differs from an older one
+ */
+export interface SnapshotDiff {
+	added: ACLEDWeeklyAggregate[];
+	removed: ACLEDWeeklyAggregate[];
+	changed: RowChange[];
+}
@@ -1 +1,2 @@
 export * from "./acled";
+export * from "./taxonomy";
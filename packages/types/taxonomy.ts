@@ -0,0 +1,77 @@
+// Code generated by taxonomy. DO NOT EDIT.
+
+import type { DisorderType, EventType, SubEventType } from "./acled";
+
+/**
+ * The sub-event types belonging to each event type
+ */
+export const SubEventTypesByEventType: Record<EventType, SubEventType[]> = {
+	"Battles": [
+		"Government regains territory",
+		"Non-state actor overtakes territory",
+		"Armed clash",
+	],
+	"Protests": [
+		"Excessive force against protesters",
+		"Protest with intervention",
+		"Peaceful protest",
+	],
+	"Riots": [
+		"Violent demonstration",
+		"Mob violence",
+	],
+	"Explosions/Remote violence": [
+		"Chemical weapon",
+		"Air/drone strike",
+		"Suicide bomb",
+		"Shelling/artillery/missile attack",
+		"Remote explosive/landmine/IED",
+		"Grenade",
+	],
+	"Violence against civilians": [
+		"Sexual violence",
+		"Attack",
+		"Abduction/forced disappearance",
+	],
+	"Strategic developments": [
+		"Agreement",
+		"Arrests",
+		"Change to group/activity",
+		"Disrupted weapons use",
+		"Headquarters or base established",
+		"Looting/property destruction",
+		"Non-violent transfer of territory",
+		"Other",
+	],
+};
+
+/**
+ * The disorder type ACLED assigns to each sub-event type
+ */
+export const DisorderTypeBySubEventType: Record<SubEventType, DisorderType> = {
+	"Government regains territory": "Political violence",
+	"Non-state actor overtakes territory": "Political violence",
+	"Armed clash": "Political violence",
+	"Excessive force against protesters": "Demonstrations",
+	"Protest with intervention": "Demonstrations",
+	"Peaceful protest": "Demonstrations",
+	"Violent demonstration": "Demonstrations",
+	"Mob violence": "Political violence",
+	"Chemical weapon": "Political violence",
+	"Air/drone strike": "Political violence",
+	"Suicide bomb": "Political violence",
+	"Shelling/artillery/missile attack": "Political violence",
+	"Remote explosive/landmine/IED": "Political violence",
+	"Grenade": "Political violence",
+	"Sexual violence": "Political violence",
+	"Attack": "Political violence",
+	"Abduction/forced disappearance": "Political violence",
+	"Agreement": "Strategic developments",
+	"Arrests": "Strategic developments",
+	"Change to group/activity": "Strategic developments",
+	"Disrupted weapons use": "Strategic developments",
+	"Headquarters or base established": "Strategic developments",
+	"Looting/property destruction": "Strategic developments",
+	"Non-violent transfer of territory": "Strategic developments",
+	"Other": "Strategic developments",
+};